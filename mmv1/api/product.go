@@ -86,13 +86,13 @@ func (p *Product) UnmarshalYAML(unmarshal func(any) error) error {
 
 func (p *Product) Validate() {
 	if len(p.Name) == 0 {
-		log.Fatalf("Missing `name` for product")
+		validationFailure("Missing `name` for product")
 	}
 
 	// product names must start with a capital
 	for i, ch := range p.Name {
 		if !unicode.IsUpper(ch) {
-			log.Fatalf("product name `%s` must start with a capital letter.", p.Name)
+			validationFailure("product name `%s` must start with a capital letter.", p.Name)
 		}
 		if i == 0 {
 			break
@@ -100,11 +100,11 @@ func (p *Product) Validate() {
 	}
 
 	if len(p.Scopes) == 0 {
-		log.Fatalf("Missing `scopes` for product %s", p.Name)
+		validationFailure("Missing `scopes` for product %s", p.Name)
 	}
 
 	if p.Versions == nil {
-		log.Fatalf("Missing `versions` for product %s", p.Name)
+		validationFailure("Missing `versions` for product %s", p.Name)
 	}
 
 	for _, v := range p.Versions {
@@ -114,6 +114,12 @@ func (p *Product) Validate() {
 	if p.Async != nil {
 		p.Async.Validate()
 	}
+
+	for _, r := range p.Objects {
+		for _, prop := range r.AllProperties() {
+			prop.validateResourceRefImports(r.Name)
+		}
+	}
 }
 
 // ====================
@@ -283,6 +289,11 @@ func Merge(self, otherObj reflect.Value) {
 			continue
 		}
 
+		// Keep the position declared in the base YAML
+		if selfObj.Type().Field(i).Name == "SourceIndex" && !selfObj.Field(i).IsZero() {
+			continue
+		}
+
 		if selfObj.Field(i).Kind() == reflect.Slice {
 			DeepMerge(selfObj.Field(i), otherObj.Field(i))
 		} else {
@@ -334,7 +345,9 @@ func DeepMerge(arr1, arr2 reflect.Value) {
 		}
 	}
 
-	// Add any elements of arr2 that don't exist in arr1
+	// Add any elements of arr2 that don't exist in arr1, positioned after the
+	// elements of arr1
+	baseLen := arr1.Len()
 	for i := 0; i < arr2.Len(); i++ {
 		otherVal := arr2.Index(i)
 		pointer := otherVal.Kind() == reflect.Ptr
@@ -357,6 +370,9 @@ func DeepMerge(arr1, arr2 reflect.Value) {
 			}
 		}
 		if !found {
+			if index := otherVal.FieldByName("SourceIndex"); index.IsValid() && index.Int() != 0 {
+				index.SetInt(int64(baseLen) + index.Int())
+			}
 			arr1.Set(reflect.Append(arr1, arr2.Index(i)))
 		}
 	}
@@ -33,6 +33,13 @@ type Resource struct {
 	// same as :name if not overridden in provider
 	ApiName string `yaml:"api_name,omitempty"`
 
+	// [Optional] A prefix and suffix the API adds to the names of all of the
+	// resource's fields, eg. `gce` for `gceMachineType`. They're added to the
+	// property names to derive the `api_name` of the properties that don't set
+	// one, so that the Terraform names don't repeat them.
+	ApiFieldNamePrefix string `yaml:"api_field_name_prefix,omitempty"`
+	ApiFieldNameSuffix string `yaml:"api_field_name_suffix,omitempty"`
+
 	// [Required] A description of the resource that's surfaced in provider
 	// documentation.
 	Description string
@@ -123,7 +130,8 @@ type Resource struct {
 
 	// [Optional] An ordered list of names of parameters that uniquely identify
 	// the resource.
-	// Generally, it's safe to leave empty, in which case it defaults to `name`.
+	// Generally, it's safe to leave empty, in which case it defaults to the
+	// `server_generated_id` field if there is one, or `name`.
 	// Other values are normally useful in cases where an object has a parent
 	// and is identified by some non-name value, such as an ip+port pair.
 	// If you're writing a fine-grained resource (eg with nested_query) a value
@@ -251,6 +259,12 @@ type Resource struct {
 	// Do not apply the default attribution label
 	ExcludeAttributionLabel bool `yaml:"exclude_attribution_label,omitempty"`
 
+	// The name of the Fingerprint property that guards label updates, eg.
+	// `labelFingerprint`. The top-level labels field and the fingerprint are
+	// then updated together through `{{self_link}}/setLabels` unless they set
+	// their own `update_url`, so the fingerprint is sent with the labels.
+	LabelsFingerprint string `yaml:"labels_fingerprint,omitempty"`
+
 	// This block inserts the named function and its attribute into the
 	// resource schema -- the code for the migrate_state function must
 	// be included in the resource constants or come from tpgresource
@@ -345,6 +359,9 @@ func (r *Resource) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 
+	recordSourceIndexes(r.Properties)
+	recordSourceIndexes(r.Parameters)
+
 	return nil
 }
 
@@ -379,6 +396,8 @@ func (r *Resource) SetDefault(product *Product) {
 	}
 
 	r.ProductMetadata = product
+	recordGeneratedIndexes(r.Properties)
+	recordGeneratedIndexes(r.Parameters)
 	for _, property := range r.AllProperties() {
 		property.SetDefault(r)
 	}
@@ -396,11 +415,11 @@ func (r *Resource) SetDefault(product *Product) {
 
 func (r *Resource) Validate() {
 	if r.Name == "" {
-		log.Fatalf("Missing `name` for resource")
+		validationFailure("Missing `name` for resource")
 	}
 
 	if r.NestedQuery != nil && r.NestedQuery.IsListOfIds && len(r.Identity) != 1 {
-		log.Fatalf("`is_list_of_ids: true` implies resource has exactly one `identity` property")
+		validationFailure("`is_list_of_ids: true` implies resource has exactly one `identity` property")
 	}
 
 	// Ensures we have all properties defined
@@ -409,38 +428,38 @@ func (r *Resource) Validate() {
 			return p.Name == i
 		})
 		if !hasIdentify {
-			log.Fatalf("Missing property/parameter for identity %s", i)
+			validationFailure("Missing property/parameter for identity %s", i)
 		}
 	}
 
 	if r.Description == "" {
-		log.Fatalf("Missing `description` for resource %s", r.Name)
+		validationFailure("Missing `description` for resource %s", r.Name)
 	}
 
 	if !r.Exclude {
 		if len(r.Properties) == 0 {
-			log.Fatalf("Missing `properties` for resource %s", r.Name)
+			validationFailure("Missing `properties` for resource %s", r.Name)
 		}
 	}
 
 	allowed := []string{"POST", "PUT", "PATCH"}
 	if !slices.Contains(allowed, r.CreateVerb) {
-		log.Fatalf("Value on `create_verb` should be one of %#v", allowed)
+		validationFailure("Value on `create_verb` should be one of %#v", allowed)
 	}
 
 	allowed = []string{"GET", "POST"}
 	if !slices.Contains(allowed, r.ReadVerb) {
-		log.Fatalf("Value on `read_verb` should be one of %#v", allowed)
+		validationFailure("Value on `read_verb` should be one of %#v", allowed)
 	}
 
 	allowed = []string{"POST", "PUT", "PATCH", "DELETE"}
 	if !slices.Contains(allowed, r.DeleteVerb) {
-		log.Fatalf("Value on `delete_verb` should be one of %#v", allowed)
+		validationFailure("Value on `delete_verb` should be one of %#v", allowed)
 	}
 
 	allowed = []string{"POST", "PUT", "PATCH"}
 	if !slices.Contains(allowed, r.UpdateVerb) {
-		log.Fatalf("Value on `update_verb` should be one of %#v", allowed)
+		validationFailure("Value on `update_verb` should be one of %#v", allowed)
 	}
 
 	for _, property := range r.AllProperties() {
@@ -462,6 +481,384 @@ func (r *Resource) Validate() {
 	if r.Async != nil {
 		r.Async.Validate()
 	}
+
+	r.validateGroupMembers()
+
+	r.validateApiFieldNames()
+
+	r.validateLabelsFingerprint()
+
+	r.validateServerGeneratedId()
+
+	r.validateNestedLabels()
+
+	for _, p := range r.PotentialPerpetualDiffFields() {
+		log.Printf("WARNING: Property %s in resource %s may show a diff on every plan: %s.", p.Lineage(), r.Name, p.perpetualDiffReason())
+	}
+
+	if !r.StateUpgraders {
+		for _, u := range r.RequiredStateUpgraders() {
+			log.Printf("WARNING: Resource %s has fields that changed type after schema version %d, but doesn't set `state_upgraders`. Add a StateUpgrader to %s, eg.:\n%s", r.Name, u.Version, r.StateMigrationFile(), u.Scaffold())
+		}
+	}
+
+	for _, group := range r.UnsatisfiableConflictGroups() {
+		log.Printf("WARNING: Conflicting properties %s in resource %s are required to be set together by other constraints, which makes a valid configuration impossible", group, r.Name)
+	}
+}
+
+// DetectConflictCycles treats the `conflicts` entries of the resource's
+// properties as an undirected graph over their Terraform lineages, and returns
+// the members of each connected group of properties whose conflicts form a
+// cycle. Groups where every member declares a conflict with every other member
+// are the usual way of expressing mutually exclusive fields and aren't
+// reported. Entries that don't resolve to a property are ignored, as those are
+// caught by the existence checks. A cycle alone doesn't make a configuration
+// invalid; UnsatisfiableConflictGroups reports the groups that do.
+func (r Resource) DetectConflictCycles() []string {
+	props := r.AllNestedProperties(r.RootProperties())
+
+	declared := make(map[string]map[string]bool)
+	for _, p := range props {
+		declared[p.TerraformLineage()] = make(map[string]bool)
+	}
+
+	parents := make(map[string]string)
+	var find func(string) string
+	find = func(n string) string {
+		if _, ok := parents[n]; !ok {
+			parents[n] = n
+		}
+		if parents[n] != n {
+			parents[n] = find(parents[n])
+		}
+		return parents[n]
+	}
+
+	edges := make(map[[2]string]bool)
+	for _, p := range props {
+		from := p.TerraformLineage()
+		for _, to := range p.Conflicting() {
+			if _, ok := declared[to]; !ok || to == from {
+				continue
+			}
+			declared[from][to] = true
+
+			edge := [2]string{from, to}
+			if to < from {
+				edge = [2]string{to, from}
+			}
+			edges[edge] = true
+			parents[find(from)] = find(to)
+		}
+	}
+
+	nodeCount := make(map[string]int)
+	for n := range parents {
+		nodeCount[find(n)]++
+	}
+	edgeCount := make(map[string]int)
+	for edge := range edges {
+		edgeCount[find(edge[0])]++
+	}
+
+	var cycles []string
+	for root, edgeTotal := range edgeCount {
+		// A connected undirected graph without cycles is a tree, which always
+		// has one fewer edge than it has nodes.
+		if edgeTotal < nodeCount[root] {
+			continue
+		}
+
+		var members []string
+		for n := range parents {
+			if find(n) == root {
+				members = append(members, n)
+			}
+		}
+
+		mutuallyExclusive := true
+		for _, a := range members {
+			for _, b := range members {
+				if a != b && !declared[a][b] {
+					mutuallyExclusive = false
+				}
+			}
+		}
+		if mutuallyExclusive {
+			continue
+		}
+
+		sort.Strings(members)
+		cycles = append(cycles, strings.Join(members, ", "))
+	}
+	sort.Strings(cycles)
+
+	return cycles
+}
+
+// UnsatisfiableConflictGroups treats the `conflicts` entries of the
+// resource's properties as an undirected graph over their Terraform lineages,
+// and returns the members of each connected group of conflicting properties
+// that no configuration can satisfy. A group is only reported when other
+// constraints require two of its conflicting members to be set together:
+// either both are always set, as required properties (and the properties they
+// pull in through `required_with`) are, or every member of some
+// `exactly_one_of` group pulls in such a pair. Conflicts alone, including the
+// cycles reported by DetectConflictCycles, can always be satisfied by leaving
+// fields unset. Entries that don't resolve to a property are ignored, as those
+// are caught by the existence checks. This is advisory; the result is only
+// used for warnings.
+func (r Resource) UnsatisfiableConflictGroups() []string {
+	props := r.AllNestedProperties(r.RootProperties())
+
+	exists := make(map[string]bool)
+	for _, p := range props {
+		exists[p.TerraformLineage()] = true
+	}
+
+	parents := make(map[string]string)
+	var find func(string) string
+	find = func(n string) string {
+		if _, ok := parents[n]; !ok {
+			parents[n] = n
+		}
+		if parents[n] != n {
+			parents[n] = find(parents[n])
+		}
+		return parents[n]
+	}
+
+	conflicting := make(map[[2]string]bool)
+	for _, p := range props {
+		from := p.TerraformLineage()
+		for _, to := range p.Conflicting() {
+			if !exists[to] || to == from {
+				continue
+			}
+			conflicting[[2]string{from, to}] = true
+			conflicting[[2]string{to, from}] = true
+			parents[find(from)] = find(to)
+		}
+	}
+
+	closures := r.RequiredWithClosure()
+	// adds a property and everything it requires to the set
+	with := func(set map[string]bool, lineage string) {
+		set[lineage] = true
+		for _, member := range closures[lineage] {
+			set[member] = true
+		}
+	}
+	// returns the groups of the conflicting pairs that are all set
+	violated := func(set map[string]bool) []string {
+		var roots []string
+		for edge := range conflicting {
+			if set[edge[0]] && set[edge[1]] {
+				roots = append(roots, find(edge[0]))
+			}
+		}
+		return roots
+	}
+
+	// Nested required properties are only set when their parent is. Parents
+	// are listed before their children.
+	always := make(map[string]bool)
+	for _, p := range props {
+		if !p.Required || p.Output {
+			continue
+		}
+		if p.ParentMetadata == nil || always[p.ParentMetadata.TerraformLineage()] {
+			with(always, p.TerraformLineage())
+		}
+	}
+
+	unsatisfiable := make(map[string]bool)
+	for _, root := range violated(always) {
+		unsatisfiable[root] = true
+	}
+
+	for _, p := range props {
+		var blocked []string
+		satisfiable := false
+		for _, member := range p.ExactlyOneOfList() {
+			lineage := p.GetPropertySchemaPath(member)
+			if !exists[lineage] {
+				continue
+			}
+			set := make(map[string]bool)
+			for m := range always {
+				set[m] = true
+			}
+			with(set, lineage)
+			roots := violated(set)
+			if len(roots) == 0 {
+				satisfiable = true
+				break
+			}
+			blocked = append(blocked, roots...)
+		}
+		if !satisfiable {
+			for _, root := range blocked {
+				unsatisfiable[root] = true
+			}
+		}
+	}
+
+	var groups []string
+	for root := range unsatisfiable {
+		var members []string
+		for n := range parents {
+			if find(n) == root {
+				members = append(members, n)
+			}
+		}
+		sort.Strings(members)
+		groups = append(groups, strings.Join(members, ", "))
+	}
+	sort.Strings(groups)
+
+	return groups
+}
+
+// Checks the members of each property's `conflicts`, `at_least_one_of`,
+// `exactly_one_of` and `required_with` groups. Listing an output or
+// url_param_only field fails, as it would make the group impossible to
+// satisfy, except in `conflicts`, where it only warns. A property listing
+// itself in `conflicts` or `required_with` fails. Entries that don't resolve
+// to a property are ignored, unless in strict mode.
+func (r Resource) validateGroupMembers() {
+	props := r.AllNestedProperties(r.RootProperties())
+
+	byLineage := make(map[string]*Type)
+	for _, p := range props {
+		byLineage[p.TerraformLineage()] = p
+	}
+
+	for _, p := range props {
+		// at_least_one_of and exactly_one_of list every member of the group,
+		// including the property itself
+		groups := []struct {
+			name        string
+			members     []string
+			includeSelf bool
+		}{
+			{"conflicts", p.Conflicting(), false},
+			{"at_least_one_of", p.AtLeastOneOfList(), true},
+			{"exactly_one_of", p.ExactlyOneOfList(), true},
+			{"required_with", p.RequiredWithList(), false},
+		}
+		for _, group := range groups {
+			for _, entry := range group.members {
+				member, ok := byLineage[p.GetPropertySchemaPath(entry)]
+				if !ok {
+					if strictValidation {
+						validationFailure("Property %s in resource %s lists %s in `%s`, which does not exist.", p.Lineage(), r.Name, entry, group.name)
+					}
+					continue
+				}
+				if member == p && !group.includeSelf {
+					validationFailure("Property %s in resource %s lists itself in `%s`.", p.Lineage(), r.Name, group.name)
+					continue
+				}
+				if !(member.Output || member.UrlParamOnly) {
+					continue
+				}
+				// conflicting with a field the user can't set is pointless,
+				// but doesn't make the configuration unsatisfiable
+				if group.name == "conflicts" {
+					log.Printf("WARNING: Property %s in resource %s lists %s in `conflicts`, which is an output or url_param_only field.", p.Lineage(), r.Name, entry)
+					continue
+				}
+				validationFailure("Property %s in resource %s lists %s in `%s`, which is an output or url_param_only field.", p.Lineage(), r.Name, entry, group.name)
+			}
+		}
+	}
+}
+
+// RequiredWithClosure returns, for each property that declares
+// `required_with`, the Terraform lineages of every field that must be set
+// together with it, following `required_with` transitively: if A requires B
+// and B requires C, the closure of A is A, B and C. Each closure includes the
+// property itself and is sorted. Entries are normalised through
+// GetPropertySchemaPath, so `parent.child` and `parent.0.child` are the same
+// field.
+func (r Resource) RequiredWithClosure() map[string][]string {
+	declared := make(map[string][]string)
+	for _, p := range r.AllNestedProperties(r.RootProperties()) {
+		if len(p.RequiredWithList()) > 0 {
+			declared[p.TerraformLineage()] = p.GetPropertySchemaPathList(p.RequiredWithList())
+		}
+	}
+
+	closures := make(map[string][]string)
+	for lineage := range declared {
+		seen := map[string]bool{lineage: true}
+		queue := []string{lineage}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range declared[current] {
+				if !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		closure := make([]string, 0, len(seen))
+		for member := range seen {
+			closure = append(closure, member)
+		}
+		sort.Strings(closure)
+		closures[lineage] = closure
+	}
+
+	return closures
+}
+
+// A property that is excluded from generation, and why.
+type ExcludedField struct {
+	Lineage string
+	Reason  string
+}
+
+// ExcludedFields returns the excluded properties of the resource, including
+// nested ones, with the `exclude_reason` given for each. The children of an
+// excluded property aren't listed separately.
+func (r Resource) ExcludedFields() []ExcludedField {
+	var excluded []ExcludedField
+	var walk func(props []*Type)
+	walk = func(props []*Type) {
+		for _, p := range props {
+			if p.Exclude {
+				excluded = append(excluded, ExcludedField{Lineage: p.Lineage(), Reason: p.ExcludeReason})
+				continue
+			}
+			switch {
+			case p.IsA("NestedObject"):
+				walk(p.Properties)
+			case p.IsA("Array") && p.ItemType != nil:
+				walk(p.ItemType.Properties)
+			case p.IsA("Map") && p.ValueType != nil:
+				walk(p.ValueType.Properties)
+			}
+		}
+	}
+	walk(r.AllProperties())
+
+	return excluded
+}
+
+// ApiToTerraformFieldMap returns a lookup table from the dotted API path of
+// each property, built from the api_name of each level, to its Terraform
+// attribute path, for rewriting API field references in error messages.
+func (r Resource) ApiToTerraformFieldMap() map[string]string {
+	fields := make(map[string]string)
+	for _, p := range r.AllNestedProperties(r.RootProperties()) {
+		fields[p.ApiLineage()] = p.TerraformLineage()
+	}
+	return fields
 }
 
 // ====================
@@ -485,15 +882,27 @@ func (r Resource) PropertiesWithExcluded() []*Type {
 }
 
 func (r Resource) UserProperites() []*Type {
-	return google.Reject(r.Properties, func(p *Type) bool {
-		return p.Exclude
-	})
+	return r.UserPropertiesInScope(SCOPE_RESOURCE)
 }
 
 func (r Resource) UserParameters() []*Type {
-	return google.Reject(r.Parameters, func(p *Type) bool {
-		return p.Exclude
-	})
+	return r.UserParametersInScope(SCOPE_RESOURCE)
+}
+
+// Returns the properties generated for the given artifact, either
+// "resource" or "datasource"
+func (r Resource) UserPropertiesInScope(scope string) []*Type {
+	return sortBySourceIndex(google.Select(r.Properties, func(p *Type) bool {
+		return p.InScope(scope)
+	}))
+}
+
+// Returns the parameters generated for the given artifact, either
+// "resource" or "datasource"
+func (r Resource) UserParametersInScope(scope string) []*Type {
+	return sortBySourceIndex(google.Select(r.Parameters, func(p *Type) bool {
+		return p.InScope(scope)
+	}))
 }
 
 func (r Resource) ServiceVersion() string {
@@ -520,7 +929,13 @@ func extractVersionFromBaseUrl(baseUrl string) string {
 // both properties and parameters but without any that are excluded due to
 // version mismatches or manual exclusion
 func (r Resource) AllUserProperties() []*Type {
-	return google.Concat(r.UserProperites(), r.UserParameters())
+	return r.AllUserPropertiesInScope(SCOPE_RESOURCE)
+}
+
+// Returns the properties and parameters generated for the given artifact,
+// either "resource" or "datasource"
+func (r Resource) AllUserPropertiesInScope(scope string) []*Type {
+	return google.Concat(r.UserPropertiesInScope(scope), r.UserParametersInScope(scope))
 }
 
 func (r Resource) RequiredProperties() []*Type {
@@ -547,6 +962,14 @@ func (r Resource) SensitiveProps() []*Type {
 	})
 }
 
+// Returns whether any of the sensitive properties is an output-only
+// attribute rather than an argument.
+func (r Resource) HasSensitiveOutputs() bool {
+	return slices.ContainsFunc(r.SensitiveProps(), func(p *Type) bool {
+		return p.Output
+	})
+}
+
 func (r Resource) SensitivePropsToString() string {
 	var props []string
 
@@ -569,7 +992,7 @@ func (r Resource) SettableProperties() []*Type {
 	})
 
 	props = google.Reject(props, func(v *Type) bool {
-		return v.UrlParamOnly
+		return v.UrlParamOnly || v.ClientSide
 	})
 
 	props = google.Reject(props, func(v *Type) bool {
@@ -579,20 +1002,368 @@ func (r Resource) SettableProperties() []*Type {
 	return props
 }
 
+// Returns the keys of the create or update request body, including dotted
+// keys for nested fields, by API name. See Type.RequestBodyKeys.
+func (r Resource) RequestBodyKeys() []string {
+	var keys []string
+	for _, p := range r.AllUserProperties() {
+		keys = append(keys, p.RequestBodyKeys()...)
+	}
+	return keys
+}
+
 func (r Resource) IsSettableProperty(t *Type) bool {
 	return slices.Contains(r.SettableProperties(), t)
 }
 
-func (r Resource) UnorderedListProperties() []*Type {
-	return google.Select(r.SettableProperties(), func(t *Type) bool {
-		return t.UnorderedList
+// Returns the properties, including nested ones, that set
+// `force_new_if_set` and need a CustomizeDiff.
+func (r Resource) ForceNewIfSetProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.ForceNewIfSet
+	})
+}
+
+// Returns the properties that set `aliases` and need a CustomizeDiff to copy
+// their value into their aliases.
+func (r Resource) AliasedProperties() []*Type {
+	return google.Select(r.RootProperties(), func(p *Type) bool {
+		return len(p.Aliases) > 0
+	})
+}
+
+// Returns the aliases of the top-level property with the given Terraform name.
+func (r Resource) FieldAliases(name string) []string {
+	for _, p := range r.RootProperties() {
+		if google.Underscore(p.Name) == name {
+			return p.Aliases
+		}
+	}
+	return nil
+}
+
+// Returns the properties that set `default_from_field` and need a
+// CustomizeDiff to copy the value of their sibling.
+func (r Resource) DefaultFromFieldProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.DefaultFromField != ""
+	})
+}
+
+// Returns the properties that set `diff_suppress_in_customize_diff` and
+// suppress their diff in a CustomizeDiff rather than on the schema.
+func (r Resource) CustomizeDiffSuppressProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.DiffSuppressInCustomizeDiff
+	})
+}
+
+// Returns the Arrays that set `unique_items`, which are checked for
+// duplicate items in a CustomizeDiff.
+func (r Resource) UniqueItemsProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.UniqueItems
+	})
+}
+
+// Returns the labels fields nested in a single object other than "metadata",
+// whose terraform_labels and effective_labels are set in a CustomizeDiff on
+// the enclosing block. The top-level block must be computed, see
+// validateNestedLabels.
+func (r Resource) NestedLabelsProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.IsA("KeyValueLabels") && p.isNestedLabels() && !p.ParentMetadata.FlattenObject && p.ParentMetadata.Name != "metadata"
+	})
+}
+
+// Returns the properties, including nested ones, whose diff-time behavior is
+// implemented in the resource's CustomizeDiff, in tree order.
+func (r Resource) CustomizeDiffContributors() []*Type {
+	contributing := make(map[*Type]bool)
+	for _, props := range [][]*Type{
+		r.ForceNewIfSetProperties(),
+		r.DefaultFromFieldProperties(),
+		r.AliasedProperties(),
+		r.CustomizeDiffSuppressProperties(),
+		r.UniqueItemsProperties(),
+		r.NestedLabelsProperties(),
+	} {
+		for _, p := range props {
+			contributing[p] = true
+		}
+	}
+
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return contributing[p] || p.ItemScopedExactlyOneOf() != nil
+	})
+}
+
+// Returns whether the resource needs a CustomizeDiff, either for the
+// properties contributing to it, a `custom_diff`, or the provider-level
+// defaults of its project, region or zone.
+func (r Resource) RequiresCustomizeDiff() bool {
+	if len(r.CustomDiff) > 0 || len(r.CustomizeDiffContributors()) > 0 {
+		return true
+	}
+
+	return (r.HasProject() || r.HasRegion() || r.HasZone()) && !r.ExcludeDefaultCdiff
+}
+
+// Returns the properties, including nested ones, that the API can filter on
+// server-side and list-style data sources can send as query parameters.
+func (r Resource) FilterableProperties() []*Type {
+	return google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.Filterable
+	})
+}
+
+// A group of fields within each element of an Array of objects, of which
+// exactly one must be set.
+type ItemExactlyOneOf struct {
+	// The path of the Array, with "*" in place of the index of each enclosing
+	// Array, eg. "rules.*.matches"
+	ListPath string
+
+	// The element-relative names of the fields in the group
+	Fields []string
+}
+
+// Returns the distinct `exactly_one_of` groups declared within the item
+// objects of Arrays, which need a CustomizeDiff to be checked per element.
+func (r Resource) ItemExactlyOneOfGroups() []ItemExactlyOneOf {
+	var groups []ItemExactlyOneOf
+	for _, p := range r.AllNestedProperties(r.RootProperties()) {
+		fields := p.ItemScopedExactlyOneOf()
+		if fields == nil {
+			continue
+		}
+
+		fields = slices.Clone(fields)
+		slices.Sort(fields)
+		group := ItemExactlyOneOf{ListPath: p.ParentMetadata.ParentMetadata.pathPattern(), Fields: fields}
+		if !slices.ContainsFunc(groups, func(g ItemExactlyOneOf) bool {
+			return g.ListPath == group.ListPath && slices.Equal(g.Fields, group.Fields)
+		}) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// A group of fields constrained together by `conflicts`, `exactly_one_of`,
+// `at_least_one_of` or `required_with`, collected for documentation.
+type ConstraintGroup struct {
+	// One of "exactly_one_of", "at_least_one_of", "conflicts" or
+	// "required_with"
+	Kind string
+
+	// The Terraform lineages of the fields in the group, sorted. For
+	// "conflicts" and "required_with" groups that aren't Mutual, the field
+	// declaring the constraint comes first, followed by the others sorted.
+	Fields []string
+
+	// "conflicts" and "required_with" only. Whether every field in the group
+	// declares the constraint with every other.
+	Mutual bool
+}
+
+// Constraint kinds, in the order ConstraintGroups returns them.
+var constraintKinds = []string{"exactly_one_of", "at_least_one_of", "conflicts", "required_with"}
+
+// Returns a sentence describing the constraint for documentation, with the
+// fields as user-facing dotted paths. When every field is in the same nested
+// block, the sentence names that block and the fields are relative to it.
+func (g ConstraintGroup) Description() string {
+	parent := constraintParent(g.Fields)
+	fields := make([]string, len(g.Fields))
+	for i, f := range g.Fields {
+		if parent != "" {
+			f = strings.TrimPrefix(f, parent+".0.")
+		}
+		fields[i] = fmt.Sprintf("`%s`", strings.ReplaceAll(f, ".0.", "."))
+	}
+
+	var description string
+	switch {
+	case g.Kind == "exactly_one_of":
+		description = fmt.Sprintf("Exactly one of %s must be set.", strings.Join(fields, ", "))
+	case g.Kind == "at_least_one_of":
+		description = fmt.Sprintf("At least one of %s must be set.", strings.Join(fields, ", "))
+	case g.Kind == "conflicts" && g.Mutual:
+		description = fmt.Sprintf("Only one of %s can be set.", strings.Join(fields, ", "))
+	case g.Kind == "conflicts":
+		description = fmt.Sprintf("%s cannot be set together with %s.", fields[0], strings.Join(fields[1:], ", "))
+	case g.Kind == "required_with" && g.Mutual:
+		description = fmt.Sprintf("%s must be set together.", strings.Join(fields, ", "))
+	case g.Kind == "required_with":
+		description = fmt.Sprintf("%s can only be set together with %s.", fields[0], strings.Join(fields[1:], ", "))
+	}
+
+	if parent == "" || description == "" {
+		return description
+	}
+	return fmt.Sprintf("In the `%s` block, %s%s", strings.ReplaceAll(parent, ".0.", "."), strings.ToLower(description[:1]), description[1:])
+}
+
+// Returns the Terraform lineage of the nested block that contains every one
+// of fields, or "" if they aren't all in the same nested block.
+func constraintParent(fields []string) string {
+	var parent string
+	for i, f := range fields {
+		index := strings.LastIndex(f, ".0.")
+		if index == -1 || (i > 0 && f[:index] != parent) {
+			return ""
+		}
+		parent = f[:index]
+	}
+	return parent
+}
+
+// Returns the distinct constraint groups declared across all properties of
+// the resource, resolved to Terraform lineages and grouped by kind, in the
+// order they're first declared. Groups of fewer than two fields, or whose
+// fields don't resolve, are skipped.
+func (r Resource) ConstraintGroups() []ConstraintGroup {
+	props := r.AllNestedProperties(r.AllUserProperties())
+
+	declared := make(map[string][][]string)
+	// pairs of fields with a "conflicts" or "required_with" constraint,
+	// by kind
+	pairs := map[string]map[[2]string]bool{"conflicts": {}, "required_with": {}}
+	for _, kind := range constraintKinds {
+		for _, p := range props {
+			var fields []string
+			switch kind {
+			case "exactly_one_of":
+				fields = p.GetPropertySchemaPathList(p.ExactlyOneOfList())
+			case "at_least_one_of":
+				fields = p.GetPropertySchemaPathList(p.AtLeastOneOfList())
+			case "conflicts":
+				fields = p.GetPropertySchemaPathList(p.Conflicting())
+			case "required_with":
+				fields = p.GetPropertySchemaPathList(p.RequiredWithList())
+			}
+			if kind == "conflicts" || kind == "required_with" {
+				fields = google.Reject(fields, func(f string) bool {
+					return f == p.TerraformLineage()
+				})
+			}
+			slices.Sort(fields)
+			fields = slices.Compact(fields)
+			if kind == "conflicts" || kind == "required_with" {
+				fields = append([]string{p.TerraformLineage()}, fields...)
+			}
+			if len(fields) < 2 {
+				continue
+			}
+
+			declared[kind] = append(declared[kind], fields)
+			if pairs[kind] != nil {
+				for _, f := range fields[1:] {
+					pairs[kind][[2]string{fields[0], f}] = true
+				}
+			}
+		}
+	}
+
+	var groups []ConstraintGroup
+	for _, kind := range constraintKinds {
+		for _, fields := range declared[kind] {
+			group := ConstraintGroup{Kind: kind, Fields: fields}
+			if pairs[kind] != nil {
+				group.Mutual = true
+				for _, a := range fields {
+					for _, b := range fields {
+						group.Mutual = group.Mutual && (a == b || pairs[kind][[2]string{a, b}])
+					}
+				}
+				if group.Mutual {
+					group.Fields = slices.Clone(fields)
+					slices.Sort(group.Fields)
+				}
+			}
+
+			if !slices.ContainsFunc(groups, func(g ConstraintGroup) bool {
+				return g.Kind == group.Kind && g.Mutual == group.Mutual && slices.Equal(g.Fields, group.Fields)
+			}) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// Returns the API name of a property named name, with the resource's
+// `api_field_name_prefix` and `api_field_name_suffix` added, eg. gceMachineType
+// for machineType.
+func (r Resource) ApiFieldName(name string) string {
+	if r.ApiFieldNamePrefix != "" && name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return r.ApiFieldNamePrefix + name + r.ApiFieldNameSuffix
+}
+
+// Returns the property name for an API field name, with the resource's
+// `api_field_name_prefix` and `api_field_name_suffix` removed, the inverse
+// of ApiFieldName.
+func (r Resource) PropertyName(apiName string) string {
+	name, hasPrefix := strings.CutPrefix(apiName, r.ApiFieldNamePrefix)
+	name, hasSuffix := strings.CutSuffix(name, r.ApiFieldNameSuffix)
+	if !hasPrefix || !hasSuffix || name == "" {
+		return apiName
+	}
+	if r.ApiFieldNamePrefix != "" {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	return name
+}
+
+// Checks that the API field name prefix and suffix are valid, and that the
+// API names they derive don't collide with those of sibling properties.
+func (r Resource) validateApiFieldNames() {
+	if r.ApiFieldNamePrefix == "" && r.ApiFieldNameSuffix == "" {
+		return
+	}
+
+	for _, affix := range []string{r.ApiFieldNamePrefix, r.ApiFieldNameSuffix} {
+		if affix != "" && !apiFieldNameAffixRegex.MatchString(affix) {
+			validationFailure("`api_field_name_prefix` and `api_field_name_suffix` of resource %s should only contain letters, digits and underscores, got %s.", r.Name, affix)
+		}
+	}
+
+	siblings := [][]*Type{r.AllUserProperties()}
+	for _, p := range r.AllNestedProperties(r.AllUserProperties()) {
+		if nested := p.NestedProperties(); nested != nil {
+			siblings = append(siblings, nested)
+		}
+	}
+	for _, props := range siblings {
+		seen := make(map[string]*Type)
+		for _, p := range props {
+			if p.UrlParamOnly {
+				continue
+			}
+			if other, ok := seen[p.ApiName]; ok {
+				validationFailure("Properties %s and %s in resource %s have the same API name %s.", other.Lineage(), p.Lineage(), r.Name, p.ApiName)
+			}
+			seen[p.ApiName] = p
+		}
+	}
+}
+
+// Returns the properties whose combination of settings, such as
+// `ignore_read`, `output`, `default_from_api` and custom flattens, is likely
+// to show a diff on every plan. The check is a heuristic, so it only warns.
+func (r *Resource) PotentialPerpetualDiffFields() []*Type {
+	return google.Select(r.AllNestedProperties(r.AllUserProperties()), func(p *Type) bool {
+		return p.perpetualDiffReason() != ""
 	})
 }
 
 // Properties that will be returned in the API body
 func (r Resource) GettableProperties() []*Type {
 	return google.Reject(r.AllUserProperties(), func(v *Type) bool {
-		return v.UrlParamOnly
+		return v.UrlParamOnly || v.ClientSide
 	})
 }
 
@@ -638,11 +1409,66 @@ func (r Resource) GetIdentity() []*Type {
 		return identities
 	}
 
+	if f := r.ServerGeneratedIdField(); f != nil {
+		return []*Type{f}
+	}
+
 	return google.Select(props, func(p *Type) bool {
 		return p.Name == "name"
 	})
 }
 
+// Returns the field marked `server_generated_id`, which identifies the
+// resource in place of `name`, or nil if there is none.
+func (r Resource) ServerGeneratedIdField() *Type {
+	for _, p := range r.AllUserProperties() {
+		if p.ServerGeneratedId {
+			return p
+		}
+	}
+	return nil
+}
+
+// Checks that at most one field is marked `server_generated_id`, and that it
+// is a top-level Output field.
+func (r Resource) validateServerGeneratedId() {
+	ids := google.Select(r.AllNestedProperties(r.RootProperties()), func(p *Type) bool {
+		return p.ServerGeneratedId
+	})
+	if len(ids) > 1 {
+		var names []string
+		for _, p := range ids {
+			names = append(names, p.Lineage())
+		}
+		validationFailure("Resource %s has more than one `server_generated_id` field: %s", r.Name, strings.Join(names, ", "))
+	}
+
+	for _, p := range ids {
+		if !p.Output {
+			validationFailure("Property %s in resource %s sets `server_generated_id` and must be `output`.", p.Lineage(), r.Name)
+		}
+		if p.ParentMetadata != nil {
+			validationFailure("Property %s in resource %s sets `server_generated_id` and must be a top-level field.", p.Lineage(), r.Name)
+		}
+	}
+}
+
+// The nested labels CustomizeDiff sets the whole top-level block with SetNew,
+// which the SDK only allows on computed fields.
+func (r Resource) validateNestedLabels() {
+	for _, p := range r.NestedLabelsProperties() {
+		var root *Type
+		for ancestor := p.ParentMetadata; ancestor != nil; ancestor = ancestor.ParentMetadata {
+			if !ancestor.FlattenObject {
+				root = ancestor
+			}
+		}
+		if root != nil && !root.DefaultFromApi && root.DefaultFromField == "" && root.DefaultFromProvider == "" {
+			validationFailure("Property %s in resource %s has nested labels, so its top-level block %s must set `default_from_api`.", p.Lineage(), r.Name, root.Name)
+		}
+	}
+}
+
 func (r *Resource) AddLabelsRelatedFields(props []*Type, parent *Type) []*Type {
 	for _, p := range props {
 		if p.IsA("KeyValueLabels") {
@@ -657,6 +1483,10 @@ func (r *Resource) AddLabelsRelatedFields(props []*Type, parent *Type) []*Type {
 }
 
 func (r *Resource) addLabelsFields(props []*Type, parent *Type, labels *Type) []*Type {
+	if parent == nil && r.LabelsFingerprint != "" {
+		r.applyLabelsUpdateGroup(props, labels)
+	}
+
 	if parent == nil || parent.FlattenObject {
 		if r.ExcludeAttributionLabel {
 			r.CustomDiff = append(r.CustomDiff, "tpgresource.SetLabelsDiffWithoutAttributionLabel")
@@ -682,6 +1512,61 @@ func (r *Resource) addLabelsFields(props []*Type, parent *Type, labels *Type) []
 	return props
 }
 
+// Checks that `labels_fingerprint` names a top-level Fingerprint property, and
+// warns if an updatable resource has one guarding its labels, but the labels
+// aren't updated with it.
+func (r Resource) validateLabelsFingerprint() {
+	fingerprints := google.Select(r.RootProperties(), func(p *Type) bool {
+		return p.IsA("Fingerprint")
+	})
+
+	if r.LabelsFingerprint != "" {
+		if !slices.ContainsFunc(fingerprints, func(p *Type) bool { return p.Name == r.LabelsFingerprint }) {
+			validationFailure("`labels_fingerprint` of resource %s should name a top-level Fingerprint property, got %s.", r.Name, r.LabelsFingerprint)
+		}
+		return
+	}
+
+	labels := google.Select(r.RootProperties(), func(p *Type) bool {
+		return p.IsA("KeyValueLabels")
+	})
+	if r.Immutable || len(labels) == 0 || labels[0].FingerprintName != "" {
+		return
+	}
+	for _, f := range fingerprints {
+		if f.Name != "labelFingerprint" {
+			continue
+		}
+		if f.UpdateUrl != labels[0].UpdateUrl || f.UpdateVerb != labels[0].UpdateVerb {
+			log.Printf("WARNING: Resource %s has a %s, but its labels aren't updated with it. Set `labels_fingerprint: '%s'`, or `fingerprint_name` on the labels.", r.Name, f.Name, f.Name)
+		}
+	}
+}
+
+// Returns the update group used for the top-level labels field and its
+// fingerprint when the resource sets `labels_fingerprint`.
+func (r Resource) LabelsUpdateGroup() UpdateGroup {
+	return UpdateGroup{
+		UpdateUrl:  fmt.Sprintf("%s/setLabels", r.SelfLinkUri()),
+		UpdateVerb: "POST",
+	}
+}
+
+// Updates the labels field and the `labels_fingerprint` property through
+// LabelsUpdateGroup, unless they set their own `update_url`.
+func (r *Resource) applyLabelsUpdateGroup(props []*Type, labels *Type) {
+	group := r.LabelsUpdateGroup()
+	updated := google.Select(props, func(p *Type) bool {
+		return p == labels || p.Name == r.LabelsFingerprint
+	})
+	for _, p := range updated {
+		if p.UpdateUrl == "" {
+			p.UpdateUrl = group.UpdateUrl
+			p.UpdateVerb = group.UpdateVerb
+		}
+	}
+}
+
 func (r *Resource) HasLabelsField() bool {
 	for _, p := range r.Properties {
 		if p.Name == "labels" {
@@ -849,6 +1734,10 @@ func (r Resource) SelfLinkUri() string {
 		return r.SelfLink
 	}
 
+	if f := r.ServerGeneratedIdField(); f != nil {
+		return strings.Join([]string{r.BaseUrl, fmt.Sprintf("{{%s}}", google.Underscore(f.Name))}, "/")
+	}
+
 	return strings.Join([]string{r.BaseUrl, "{{name}}"}, "/")
 }
 
@@ -903,6 +1792,9 @@ func propertiesWithoutCustomUpdate(properties []*Type) []*Type {
 
 func (r Resource) UpdateBodyProperties() []*Type {
 	updateProp := propertiesWithoutCustomUpdate(r.SettableProperties())
+	updateProp = google.Reject(updateProp, func(p *Type) bool {
+		return p.CreateOnlyReadable
+	})
 	if r.UpdateVerb == "PATCH" {
 		updateProp = google.Reject(updateProp, func(p *Type) bool {
 			return p.Immutable
@@ -1007,7 +1899,13 @@ func (r Resource) TerraformName() string {
 }
 
 func (r Resource) ImportIdFormatsFromResource() []string {
-	return ImportIdFormats(r.ImportFormat, r.Identity, r.BaseUrl)
+	identity := r.Identity
+	if len(identity) == 0 {
+		if f := r.ServerGeneratedIdField(); f != nil {
+			identity = []string{f.Name}
+		}
+	}
+	return ImportIdFormats(r.ImportFormat, identity, r.BaseUrl)
 }
 
 // Returns a list of import id formats for a given resource. If an id
@@ -1046,7 +1944,7 @@ func ImportIdFormats(importFormat, identity []string, baseUrl string) []string {
 	}
 
 	// short id: {{project}}/{{zone}}/{{name}}
-	fieldMarkers := regexp.MustCompile(`{{[[:word:]]+}}`).FindAllString(idFormats[0], -1)
+	fieldMarkers := regexp.MustCompile(`{{[[:word:].]+}}`).FindAllString(idFormats[0], -1)
 	shortIdFormat := strings.Join(fieldMarkers, "/")
 
 	// short ids without fields with provider-level defaults:
@@ -1125,7 +2023,7 @@ func (r Resource) IgnoreReadPropertiesToString(e resource.Examples) string {
 func ignoreReadFields(props []*Type) []string {
 	var fields []string
 	for _, tp := range props {
-		if tp.IgnoreRead && !tp.UrlParamOnly && !tp.IsA("ResourceRef") {
+		if (tp.IgnoreRead || tp.ClientSide) && !tp.UrlParamOnly && !tp.IsA("ResourceRef") {
 			fields = append(fields, tp.TerraformLineage())
 		} else if tp.IsA("NestedObject") && tp.AllProperties() != nil {
 			fields = append(fields, ignoreReadFields(tp.AllProperties())...)
@@ -1618,10 +2516,13 @@ func (r Resource) CustomUpdatePropertiesByKey(properties []*Type, updateUrl stri
 	})
 }
 
+// Returns the Terraform names of the properties, including their aliases, for
+// checking whether any of them changed.
 func (r Resource) PropertyNamesToStrings(properties []*Type) []string {
 	var propertyNames []string
 	for _, prop := range properties {
 		propertyNames = append(propertyNames, google.Underscore(prop.Name))
+		propertyNames = append(propertyNames, prop.Aliases...)
 	}
 	return propertyNames
 }
@@ -1658,6 +2559,91 @@ func (r Resource) StateUpgradersCount() []int {
 	return nums
 }
 
+// A StateUpgrader that a resource needs to migrate state from a schema version
+// in which some of its fields had a different type.
+type StateUpgraderStub struct {
+	ResourceName string
+
+	// The schema version the upgrader migrates state from
+	Version int
+
+	// The fields whose type changed after Version
+	Properties []*Type
+}
+
+// Returns the StateUpgraders needed by the fields that set
+// `schema_version_changes`, ordered by the version they migrate from.
+func (r Resource) RequiredStateUpgraders() []StateUpgraderStub {
+	var stubs []StateUpgraderStub
+	for _, p := range r.RootProperties() {
+		for _, change := range p.SchemaVersionChanges {
+			i := slices.IndexFunc(stubs, func(u StateUpgraderStub) bool {
+				return u.Version == change.Version
+			})
+			if i == -1 {
+				stubs = append(stubs, StateUpgraderStub{ResourceName: r.ResourceName(), Version: change.Version})
+				i = len(stubs) - 1
+			}
+			stubs[i].Properties = append(stubs[i].Properties, p)
+		}
+	}
+
+	sort.Slice(stubs, func(i, j int) bool {
+		return stubs[i].Version < stubs[j].Version
+	})
+	return stubs
+}
+
+// Returns the type the property had in the schema version of the upgrader.
+func (u StateUpgraderStub) OldType(p *Type) string {
+	for _, change := range p.SchemaVersionChanges {
+		if change.Version == u.Version {
+			return change.Type
+		}
+	}
+	return p.Type
+}
+
+// Returns scaffolding for the upgrader, to be completed in the resource's
+// state migration file: the old schema of the changed fields and the
+// migration function. Primitive fields that became Arrays are wrapped in a
+// list; other changes are left to be filled in.
+func (u StateUpgraderStub) Scaffold() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "func resource%sResourceV%d() *schema.Resource {\n", u.ResourceName, u.Version)
+	b.WriteString("\treturn &schema.Resource{\n\t\tSchema: map[string]*schema.Schema{\n")
+	for _, p := range u.Properties {
+		fmt.Fprintf(&b, "\t\t\t%q: {\n", p.TerraformLineage())
+		fmt.Fprintf(&b, "\t\t\t\tType: %s,\n", p.TFType(u.OldType(p)))
+		switch {
+		case p.Required:
+			b.WriteString("\t\t\t\tRequired: true,\n")
+		case p.Output:
+			b.WriteString("\t\t\t\tComputed: true,\n")
+		default:
+			b.WriteString("\t\t\t\tOptional: true,\n")
+		}
+		b.WriteString("\t\t\t},\n")
+	}
+	b.WriteString("\t\t},\n\t}\n}\n\n")
+
+	fmt.Fprintf(&b, "func Resource%sUpgradeV%d(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {\n", u.ResourceName, u.Version)
+	for _, p := range u.Properties {
+		key := p.TerraformLineage()
+		if p.IsA("Array") && slices.Contains(primitiveTypes, u.OldType(p)) {
+			fmt.Fprintf(&b, "\tif v, ok := rawState[%q]; ok && v != nil {\n", key)
+			fmt.Fprintf(&b, "\t\trawState[%q] = []interface{}{v}\n", key)
+			b.WriteString("\t}\n")
+		} else {
+			fmt.Fprintf(&b, "\t// TODO: migrate %q from %s to %s\n", key, u.OldType(p), p.Type)
+		}
+	}
+	b.WriteString("\treturn rawState, nil\n}\n")
+
+	return b.String()
+}
+
 func (r Resource) CaiProductBaseUrl() string {
 	version := r.ProductMetadata.VersionObjOrClosest(r.TargetVersionName)
 	baseUrl := version.CaiBaseUrl
@@ -295,7 +295,6 @@ func ExecuteTemplate(e any, templatePath string, appendNewline bool) string {
 		"templates/terraform/expand_property_method.go.tmpl",
 		"templates/terraform/update_mask.go.tmpl",
 		"templates/terraform/nested_query.go.tmpl",
-		"templates/terraform/unordered_list_customize_diff.go.tmpl",
 	}
 	templateFileName := filepath.Base(templatePath)
 
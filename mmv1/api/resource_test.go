@@ -1,10 +1,14 @@
 package api
 
 import (
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"golang.org/x/exp/slices"
 )
 
 func TestResourceMinVersionObj(t *testing.T) {
@@ -198,3 +202,1268 @@ func TestResourceServiceVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceDetectConflictCycles(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		conflicts   map[string][]string
+		expected    []string
+	}{
+		{
+			description: "pairwise conflicts",
+			conflicts: map[string][]string{
+				"a": {"b"},
+				"b": {"a"},
+				"c": {"d"},
+				"d": {},
+			},
+			expected: nil,
+		},
+		{
+			description: "three node cycle",
+			conflicts: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"a"},
+				"d": {"a"},
+			},
+			expected: []string{"a, b, c, d"},
+		},
+		{
+			description: "mutually exclusive group",
+			conflicts: map[string][]string{
+				"a": {"b", "c"},
+				"b": {"a", "c"},
+				"c": {"a", "b"},
+			},
+			expected: nil,
+		},
+		{
+			description: "unresolved entries are ignored",
+			conflicts: map[string][]string{
+				"a": {"b", "missing"},
+				"b": {"missing"},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{Name: "test"}
+			for _, name := range []string{"a", "b", "c", "d"} {
+				r.Properties = append(r.Properties, &Type{
+					Name:             name,
+					Type:             "String",
+					Conflicts:        tc.conflicts[name],
+					ResourceMetadata: &r,
+				})
+			}
+
+			if got, want := r.DetectConflictCycles(), tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestResourceUnsatisfiableConflictGroups(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		properties  []*Type
+		expected    []string
+	}{
+		{
+			description: "pairwise conflicts",
+			properties: []*Type{
+				{Name: "a", Type: "String", Conflicts: []string{"b"}},
+				{Name: "b", Type: "String", Conflicts: []string{"a"}},
+				{Name: "c", Type: "String", Conflicts: []string{"d"}},
+				{Name: "d", Type: "String"},
+			},
+			expected: nil,
+		},
+		{
+			description: "three node cycle of optional fields",
+			properties: []*Type{
+				{Name: "a", Type: "String", Conflicts: []string{"b"}},
+				{Name: "b", Type: "String", Conflicts: []string{"c"}},
+				{Name: "c", Type: "String", Conflicts: []string{"a"}},
+			},
+			expected: nil,
+		},
+		{
+			description: "firewall rules",
+			properties: []*Type{
+				{Name: "allow", Type: "String", ExactlyOneOf: []string{"allow", "deny"}},
+				{Name: "deny", Type: "String", ExactlyOneOf: []string{"allow", "deny"}},
+				{Name: "name", Type: "String", Required: true},
+				{Name: "network", Type: "String", Required: true},
+				{Name: "sourceServiceAccounts", Type: "String", Conflicts: []string{"source_tags", "target_tags"}},
+				{Name: "sourceTags", Type: "String", Conflicts: []string{"source_service_accounts", "target_service_accounts"}},
+				{Name: "targetServiceAccounts", Type: "String", Conflicts: []string{"source_tags", "target_tags"}},
+				{Name: "targetTags", Type: "String", Conflicts: []string{"source_service_accounts", "target_service_accounts"}},
+			},
+			expected: nil,
+		},
+		{
+			description: "three node cycle with required members",
+			properties: []*Type{
+				{Name: "a", Type: "String", Required: true, Conflicts: []string{"b"}},
+				{Name: "b", Type: "String", Required: true, Conflicts: []string{"c"}},
+				{Name: "c", Type: "String", Conflicts: []string{"a"}},
+			},
+			expected: []string{"a, b, c"},
+		},
+		{
+			description: "required_with pulls in a conflicting field",
+			properties: []*Type{
+				{Name: "a", Type: "String", Required: true, RequiredWith: []string{"b"}},
+				{Name: "b", Type: "String", RequiredWith: []string{"c"}},
+				{Name: "c", Type: "String", Conflicts: []string{"a"}},
+			},
+			expected: []string{"a, c"},
+		},
+		{
+			description: "every exactly_one_of member is blocked",
+			properties: []*Type{
+				{Name: "a", Type: "String", ExactlyOneOf: []string{"a", "b"}, Conflicts: []string{"c"}},
+				{Name: "b", Type: "String", ExactlyOneOf: []string{"a", "b"}, Conflicts: []string{"c"}},
+				{Name: "c", Type: "String", Required: true},
+			},
+			expected: []string{"a, b, c"},
+		},
+		{
+			description: "one exactly_one_of member is allowed",
+			properties: []*Type{
+				{Name: "a", Type: "String", ExactlyOneOf: []string{"a", "b"}, Conflicts: []string{"c"}},
+				{Name: "b", Type: "String", ExactlyOneOf: []string{"a", "b"}},
+				{Name: "c", Type: "String", Required: true},
+			},
+			expected: nil,
+		},
+		{
+			description: "required fields of an optional parent",
+			properties: []*Type{
+				{Name: "a", Type: "String", Required: true, Conflicts: []string{"parent.0.child"}},
+				{Name: "parent", Type: "NestedObject", Properties: []*Type{
+					{Name: "child", Type: "String", Required: true},
+				}},
+			},
+			expected: nil,
+		},
+		{
+			description: "unresolved entries are ignored",
+			properties: []*Type{
+				{Name: "a", Type: "String", Required: true, Conflicts: []string{"b", "missing"}},
+				{Name: "b", Type: "String", Conflicts: []string{"missing"}},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{Name: "test", Properties: tc.properties}
+			for _, p := range r.Properties {
+				p.SetDefault(&r)
+			}
+
+			if got, want := r.UnsatisfiableConflictGroups(), tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestResourceRequiredWithClosure(t *testing.T) {
+	t.Parallel()
+
+	r := Resource{Name: "test"}
+	child := &Type{Name: "child", Type: "String", RequiredWith: []string{"c"}}
+	r.Properties = []*Type{
+		{Name: "a", Type: "String", RequiredWith: []string{"b"}},
+		{Name: "b", Type: "String", RequiredWith: []string{"c"}},
+		{Name: "c", Type: "String"},
+		{Name: "d", Type: "String"},
+		{Name: "e", Type: "String", RequiredWith: []string{"parent.child"}},
+		{Name: "parent", Type: "NestedObject", Properties: []*Type{child}},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(&r)
+	}
+
+	// `parent.child` follows the required_with of `parent.0.child`
+	expected := map[string][]string{
+		"a":              {"a", "b", "c"},
+		"b":              {"b", "c"},
+		"e":              {"c", "e", "parent.0.child"},
+		"parent.0.child": {"c", "parent.0.child"},
+	}
+	if got := r.RequiredWithClosure(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestResourceApiToTerraformFieldMap(t *testing.T) {
+	t.Parallel()
+
+	r := Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "plainField", Type: "String"},
+		{
+			Name:    "parentField",
+			ApiName: "parentApi",
+			Type:    "NestedObject",
+			Properties: []*Type{
+				{Name: "childField", ApiName: "childApi", Type: "String"},
+			},
+		},
+		{
+			Name:          "wrapper",
+			Type:          "NestedObject",
+			FlattenObject: true,
+			Properties: []*Type{
+				{Name: "flattenedField", Type: "String"},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(&r)
+	}
+
+	expected := map[string]string{
+		"plainField":             "plain_field",
+		"parentApi":              "parent_field",
+		"parentApi.childApi":     "parent_field.0.child_field",
+		"wrapper.flattenedField": "flattened_field",
+	}
+	if got := r.ApiToTerraformFieldMap(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestResourceExcludedFields(t *testing.T) {
+	t.Parallel()
+
+	r := Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "kept", Type: "String"},
+		{Name: "dropped", Type: "String", Exclude: true, ExcludeReason: "Superseded by kept."},
+		{
+			Name: "parent",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "child", Type: "String", Exclude: true},
+			},
+		},
+		{
+			Name: "list",
+			Type: "Array",
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "itemChild", Type: "String", Exclude: true, ExcludeReason: "Not supported."},
+				},
+			},
+		},
+		{
+			Name:          "droppedParent",
+			Type:          "NestedObject",
+			Exclude:       true,
+			ExcludeReason: "Deprecated.",
+			Properties: []*Type{
+				{Name: "droppedChild", Type: "String", Exclude: true},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(&r)
+	}
+
+	expected := []ExcludedField{
+		{Lineage: "dropped", Reason: "Superseded by kept."},
+		{Lineage: "parent.child", Reason: ""},
+		{Lineage: "list.list.item_child", Reason: "Not supported."},
+		{Lineage: "dropped_parent", Reason: "Deprecated."},
+	}
+	if got := r.ExcludedFields(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestResourcePropertyOrderIsStable(t *testing.T) {
+	t.Parallel()
+
+	names := func(props []*Type) []string {
+		var n []string
+		for _, p := range props {
+			n = append(n, p.Name)
+		}
+		return n
+	}
+
+	base := `
+name: test
+base_url: projects/{{project}}/tests
+parameters:
+  - name: region
+    type: String
+    url_param_only: true
+  - name: project
+    type: String
+    url_param_only: true
+properties:
+  - name: zone
+    type: String
+  - name: settings
+    type: NestedObject
+    flatten_object: true
+    properties:
+      - name: tier
+        type: String
+      - name: edition
+        type: String
+      - name: activation
+        type: String
+  - name: labels
+    type: KeyValueLabels
+  - name: alpha
+    type: String
+  - name: description
+    type: String
+`
+	override := `
+properties:
+  - name: extra
+    type: String
+  - name: description
+    type: String
+    description: The description.
+  - name: settings
+    type: NestedObject
+    properties:
+      - name: size
+        type: Integer
+      - name: tier
+        type: String
+`
+	yamlValidator := google.YamlValidator{}
+	r := &Resource{}
+	yamlValidator.Parse([]byte(base), r, "base.yaml")
+	overrideResource := &Resource{}
+	yamlValidator.Parse([]byte(override), overrideResource, "override.yaml")
+	Merge(reflect.ValueOf(r), reflect.ValueOf(*overrideResource))
+
+	settings := r.Properties[slices.IndexFunc(r.Properties, func(p *Type) bool {
+		return p.Name == "settings"
+	})]
+	shuffle := func(rng *rand.Rand) {
+		rng.Shuffle(len(r.Properties), func(a, b int) {
+			r.Properties[a], r.Properties[b] = r.Properties[b], r.Properties[a]
+		})
+		rng.Shuffle(len(r.Parameters), func(a, b int) {
+			r.Parameters[a], r.Parameters[b] = r.Parameters[b], r.Parameters[a]
+		})
+		rng.Shuffle(len(settings.Properties), func(a, b int) {
+			settings.Properties[a], settings.Properties[b] = settings.Properties[b], settings.Properties[a]
+		})
+	}
+
+	// Reorder the merged slices before the defaults are applied, so only the
+	// positions recorded from the YAML can give the declared order
+	rng := rand.New(rand.NewSource(1))
+	shuffle(rng)
+	r.Properties = r.AddLabelsRelatedFields(r.PropertiesWithExcluded(), nil)
+	r.SetDefault(&Product{Name: "test"})
+
+	// Properties only in the override follow the base ones, and generated
+	// fields come last
+	expectedUser := []string{"zone", "settings", "labels", "alpha", "description", "extra", "terraformLabels", "effectiveLabels", "region", "project"}
+	expectedRoot := []string{"zone", "tier", "edition", "activation", "size", "labels", "alpha", "description", "extra", "terraformLabels", "effectiveLabels", "region", "project"}
+	expectedNested := []string{"tier", "edition", "activation", "size"}
+
+	for i := 0; i < 10; i++ {
+		if got := names(r.AllUserProperties()); !reflect.DeepEqual(got, expectedUser) {
+			t.Errorf("AllUserProperties() = %v, want %v", got, expectedUser)
+		}
+		if got := names(r.RootProperties()); !reflect.DeepEqual(got, expectedRoot) {
+			t.Errorf("RootProperties() = %v, want %v", got, expectedRoot)
+		}
+		if got := names(settings.NestedProperties()); !reflect.DeepEqual(got, expectedNested) {
+			t.Errorf("NestedProperties() = %v, want %v", got, expectedNested)
+		}
+		shuffle(rng)
+	}
+
+	// Shared positions are ordered by name; unrecorded ones sort last in
+	// their current order
+	mixed := []*Type{{Name: "c"}, {Name: "b", SourceIndex: 1}, {Name: "a"}, {Name: "a", SourceIndex: 1}}
+	if got := names(sortBySourceIndex(mixed)); !reflect.DeepEqual(got, []string{"a", "b", "c", "a"}) {
+		t.Errorf("sortBySourceIndex() = %v, want [a b c a]", got)
+	}
+}
+
+func TestResourceValidateGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		properties  []*Type
+		fatal       bool
+	}{
+		{
+			description: "exactly_one_of with settable fields",
+			properties: []*Type{
+				{Name: "one", Type: "String", ExactlyOneOf: []string{"one", "two"}},
+				{Name: "two", Type: "String", ExactlyOneOf: []string{"one", "two"}},
+			},
+			fatal: false,
+		},
+		{
+			description: "exactly_one_of with an output field",
+			properties: []*Type{
+				{Name: "one", Type: "String", ExactlyOneOf: []string{"one", "two"}},
+				{Name: "two", Type: "String", Output: true},
+			},
+			fatal: true,
+		},
+		{
+			description: "at_least_one_of with a nested output field",
+			properties: []*Type{
+				{Name: "one", Type: "String", AtLeastOneOf: []string{"one", "parent.0.child"}},
+				{
+					Name: "parent",
+					Type: "NestedObject",
+					Properties: []*Type{
+						{Name: "child", Type: "String", Output: true},
+					},
+				},
+			},
+			fatal: true,
+		},
+		{
+			description: "required_with a url_param_only field",
+			properties: []*Type{
+				{Name: "one", Type: "String", RequiredWith: []string{"two"}},
+				{Name: "two", Type: "String", UrlParamOnly: true},
+			},
+			fatal: true,
+		},
+		{
+			description: "unresolved member",
+			properties: []*Type{
+				{Name: "one", Type: "String", ExactlyOneOf: []string{"one", "missing"}},
+			},
+			fatal: false,
+		},
+		{
+			description: "conflicts with an output field",
+			properties: []*Type{
+				{Name: "one", Type: "String", Conflicts: []string{"two"}},
+				{Name: "two", Type: "String", Output: true},
+			},
+			fatal: false,
+		},
+		{
+			description: "conflicts with itself",
+			properties: []*Type{
+				{Name: "one", Type: "String", Conflicts: []string{"two", "one"}},
+				{Name: "two", Type: "String"},
+			},
+			fatal: true,
+		},
+		{
+			description: "nested field conflicts with itself",
+			properties: []*Type{
+				{
+					Name: "parent",
+					Type: "NestedObject",
+					Properties: []*Type{
+						{Name: "child", Type: "String", Conflicts: []string{"parent.0.child"}},
+					},
+				},
+			},
+			fatal: true,
+		},
+		{
+			description: "required_with itself",
+			properties: []*Type{
+				{Name: "one", Type: "String", RequiredWith: []string{"one"}},
+			},
+			fatal: true,
+		},
+		{
+			description: "exactly_one_of including itself",
+			properties: []*Type{
+				{Name: "one", Type: "String", ExactlyOneOf: []string{"one", "two"}},
+				{Name: "two", Type: "String", ExactlyOneOf: []string{"one", "two"}},
+			},
+			fatal: false,
+		},
+		{
+			description: "at_least_one_of including itself",
+			properties: []*Type{
+				{Name: "one", Type: "String", AtLeastOneOf: []string{"one", "two"}},
+				{Name: "two", Type: "String", AtLeastOneOf: []string{"one", "two"}},
+			},
+			fatal: false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := Resource{Name: "test", ProductMetadata: &Product{Name: "test"}, Properties: tc.properties}
+			for _, p := range r.Properties {
+				p.SetDefault(&r)
+			}
+			if tc.fatal {
+				assertFatal(t, func() { r.validateGroupMembers() })
+			} else {
+				r.validateGroupMembers()
+			}
+		})
+	}
+}
+
+func TestResourceItemExactlyOneOfGroups(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "glob", Type: "String"},
+		{
+			Name:                "rules",
+			Type:                "Array",
+			ExactlyOneOfPerItem: true,
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "glob", Type: "String", ExactlyOneOf: []string{"glob", "regex"}},
+					{Name: "regex", Type: "String", ExactlyOneOf: []string{"glob", "regex"}},
+					{
+						Name:                "matches",
+						Type:                "Array",
+						ExactlyOneOfPerItem: true,
+						ItemType: &Type{
+							Type:               "NestedObject",
+							ExactlyOneOfGroups: [][]string{{"fullPathMatch", "prefixMatch"}},
+							Properties: []*Type{
+								{Name: "fullPathMatch", Type: "String"},
+								{Name: "prefixMatch", Type: "String"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "config",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{
+					Name:                "redirects",
+					Type:                "Array",
+					ExactlyOneOfPerItem: true,
+					ItemType: &Type{
+						Type: "NestedObject",
+						Properties: []*Type{
+							{Name: "path", Type: "String", ExactlyOneOf: []string{"path", "location"}},
+							{Name: "location", Type: "String", ExactlyOneOf: []string{"path", "location"}},
+						},
+					},
+				},
+				{
+					Name: "headers",
+					Type: "Array",
+					ItemType: &Type{
+						Type: "NestedObject",
+						Properties: []*Type{
+							{Name: "set", Type: "String", ExactlyOneOf: []string{"set", "remove"}},
+							{Name: "remove", Type: "String", ExactlyOneOf: []string{"set", "remove"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	expected := []ItemExactlyOneOf{
+		{ListPath: "rules", Fields: []string{"glob", "regex"}},
+		{ListPath: "rules.*.matches", Fields: []string{"full_path_match", "prefix_match"}},
+		{ListPath: "config.0.redirects", Fields: []string{"location", "path"}},
+	}
+	got := r.ItemExactlyOneOfGroups()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d groups, got %v", len(expected), got)
+	}
+	for _, e := range expected {
+		if !slices.ContainsFunc(got, func(g ItemExactlyOneOf) bool {
+			return g.ListPath == e.ListPath && slices.Equal(g.Fields, e.Fields)
+		}) {
+			t.Errorf("expected group %v, got %v", e, got)
+		}
+	}
+
+	// item-scoped groups aren't resolved from the resource root, where a
+	// top-level field may share a member's name
+	glob := r.Properties[1].ItemType.Properties[0]
+	if list := glob.ExactlyOneOfList(); len(list) != 0 {
+		t.Errorf("expected no schema-level exactly_one_of for %s, got %v", glob.Name, list)
+	}
+
+	// without `exactly_one_of_per_item`, the group is left on the schema
+	set := r.Properties[2].Properties[1].ItemType.Properties[0]
+	if list := set.ExactlyOneOfList(); !reflect.DeepEqual(list, []string{"set", "remove"}) {
+		t.Errorf("expected the schema-level exactly_one_of for %s, got %v", set.Name, list)
+	}
+}
+
+func TestResourceFilterableProperties(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String", Filterable: true},
+		{Name: "description", Type: "String"},
+		{Name: "state", Type: "Enum", Filterable: true, EnumValues: []string{"ACTIVE", "DELETED"}},
+		{Name: "createTime", Type: "Time", Output: true},
+		{
+			Name: "network",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "subnet", Type: "String", Filterable: true},
+				{Name: "mtu", Type: "Integer"},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	var got []string
+	for _, p := range r.FilterableProperties() {
+		got = append(got, p.Lineage())
+	}
+	slices.Sort(got)
+	if expected := []string{"name", "network.subnet", "state"}; !slices.Equal(got, expected) {
+		t.Errorf("expected filterable properties %v, got %v", expected, got)
+	}
+}
+
+func TestResourceRequiredStateUpgraders(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "Topic", SchemaVersion: 1, ProductMetadata: &Product{Name: "Pubsub"}}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String", Required: true},
+		{
+			Name:                 "regions",
+			Type:                 "Array",
+			ItemType:             &Type{Type: "String"},
+			SchemaVersionChanges: []SchemaVersionChange{{Version: 0, Type: "String"}},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	stubs := r.RequiredStateUpgraders()
+	if len(stubs) != 1 || stubs[0].Version != 0 || len(stubs[0].Properties) != 1 {
+		t.Fatalf("expected one upgrader from version 0 for regions, got %v", stubs)
+	}
+
+	got := stubs[0].Scaffold()
+	for _, expected := range []string{
+		"func resourcePubsubTopicResourceV0() *schema.Resource {",
+		"\"regions\": {\n\t\t\t\tType: schema.TypeString,\n\t\t\t\tOptional: true,",
+		"func ResourcePubsubTopicUpgradeV0(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {",
+		"rawState[\"regions\"] = []interface{}{v}",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected the scaffold to contain %q, got:\n%s", expected, got)
+		}
+	}
+	if strings.Contains(got, "\"name\"") {
+		t.Errorf("expected unchanged fields to be left out of the scaffold, got:\n%s", got)
+	}
+}
+
+func TestResourceConstraintGroups(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "pushConfig", Type: "String", Conflicts: []string{"bigqueryConfig", "storage_config"}},
+		{Name: "bigqueryConfig", Type: "String", Conflicts: []string{"push_config", "storage_config"}},
+		{Name: "storageConfig", Type: "String", Conflicts: []string{"push_config", "bigquery_config"}},
+		{Name: "sourceTags", Type: "String", Conflicts: []string{"source_accounts", "target_accounts"}},
+		{Name: "sourceAccounts", Type: "String"},
+		{Name: "targetAccounts", Type: "String"},
+		{Name: "certificate", Type: "String", RequiredWith: []string{"private_key"}},
+		{Name: "privateKey", Type: "String", RequiredWith: []string{"certificate"}},
+		{
+			Name: "logConfig",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "enable", Type: "Boolean", AtLeastOneOf: []string{"log_config.0.enable", "log_config.0.sample_rate"}},
+				{Name: "sampleRate", Type: "Double", AtLeastOneOf: []string{"log_config.0.sample_rate", "log_config.0.enable"}},
+				{Name: "filter", Type: "String", ExactlyOneOf: []string{"logConfig.filter", "log_config.0.missing"}},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	expected := []ConstraintGroup{
+		{Kind: "at_least_one_of", Fields: []string{"log_config.0.enable", "log_config.0.sample_rate"}},
+		{Kind: "conflicts", Fields: []string{"bigquery_config", "push_config", "storage_config"}, Mutual: true},
+		{Kind: "conflicts", Fields: []string{"source_tags", "source_accounts", "target_accounts"}},
+		{Kind: "required_with", Fields: []string{"certificate", "private_key"}, Mutual: true},
+	}
+	got := r.ConstraintGroups()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d groups, got %v", len(expected), got)
+	}
+	for i, e := range expected {
+		if got[i].Kind != e.Kind || got[i].Mutual != e.Mutual || !slices.Equal(got[i].Fields, e.Fields) {
+			t.Errorf("expected group %d to be %v, got %v", i, e, got[i])
+		}
+	}
+
+	if got, want := expected[1].Description(), "Only one of `bigquery_config`, `push_config`, `storage_config` can be set."; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+	if got, want := expected[2].Description(), "`source_tags` cannot be set together with `source_accounts`, `target_accounts`."; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+
+	// Groups within a nested block name it, and fields in different blocks
+	// are shown as dotted paths
+	if got, want := expected[0].Description(), "In the `log_config` block, at least one of `enable`, `sample_rate` must be set."; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+	nested := ConstraintGroup{Kind: "conflicts", Fields: []string{"spec.0.template.0.image", "spec.0.template.0.source"}}
+	if got, want := nested.Description(), "In the `spec.template` block, `image` cannot be set together with `source`."; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+	mixed := ConstraintGroup{Kind: "exactly_one_of", Fields: []string{"log_config.0.enable", "name"}}
+	if got, want := mixed.Description(), "Exactly one of `log_config.enable`, `name` must be set."; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+}
+
+func TestResourceUniqueItemsProperties(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "ports", Type: "Array", UniqueItems: true, ItemType: &Type{Type: "String"}},
+		{Name: "tags", Type: "Array", ItemType: &Type{Type: "String"}},
+		{
+			Name: "rules",
+			Type: "Array",
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "sourceRanges", Type: "Array", UniqueItems: true, ItemType: &Type{Type: "String"}},
+				},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	var got []string
+	for _, p := range r.UniqueItemsProperties() {
+		got = append(got, p.PathPattern())
+	}
+	if expected := []string{"ports", "rules.*.source_ranges"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestResourceApiFieldNames(t *testing.T) {
+	t.Parallel()
+
+	newResource := func() *Resource {
+		r := &Resource{Name: "test", ApiFieldNamePrefix: "gce"}
+		r.Properties = []*Type{
+			{Name: "machineType", Type: "String"},
+			{Name: "labelSet", Type: "String", ApiName: "labels"},
+			{
+				Name: "config",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "diskSize", Type: "Integer"},
+				},
+			},
+		}
+		r.Parameters = []*Type{
+			{Name: "zone", Type: "String", UrlParamOnly: true},
+		}
+		return r
+	}
+
+	r := newResource()
+	r.SetDefault(&Product{Name: "test"})
+
+	byName := make(map[string]*Type)
+	for _, p := range r.AllNestedProperties(r.AllUserProperties()) {
+		byName[p.Name] = p
+	}
+	for name, expected := range map[string]string{
+		"machineType": "gceMachineType",
+		"labelSet":    "labels",
+		"config":      "gceConfig",
+		"diskSize":    "gceDiskSize",
+		"zone":        "zone",
+	} {
+		if got := byName[name].ApiName; got != expected {
+			t.Errorf("expected the API name of %s to be %s, got %s", name, expected, got)
+		}
+	}
+	if got, expected := byName["diskSize"].ApiLineage(), "gceConfig.gceDiskSize"; got != expected {
+		t.Errorf("expected the API lineage of diskSize to be %s, got %s", expected, got)
+	}
+	for _, name := range []string{"machineType", "config", "diskSize"} {
+		if got := r.PropertyName(byName[name].ApiName); got != name {
+			t.Errorf("expected %s to round-trip, got %s", name, got)
+		}
+	}
+
+	suffixed := Resource{ApiFieldNameSuffix: "_v2"}
+	if got, expected := suffixed.ApiFieldName("machineType"), "machineType_v2"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if got, expected := suffixed.PropertyName("machineType_v2"), "machineType"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	t.Run("duplicate API names", func(t *testing.T) {
+		t.Parallel()
+
+		r := newResource()
+		r.Properties = append(r.Properties, &Type{Name: "type", Type: "String", ApiName: "gceMachineType"})
+		r.SetDefault(&Product{Name: "test"})
+		assertFatal(t, func() { r.validateApiFieldNames() })
+	})
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		t.Parallel()
+
+		r := newResource()
+		r.ApiFieldNamePrefix = "gce."
+		r.SetDefault(&Product{Name: "test"})
+		assertFatal(t, func() { r.validateApiFieldNames() })
+	})
+}
+
+func TestResourcePotentialPerpetualDiffFields(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", IdFormat: "projects/{{project}}/zones/{{zone}}/tests/{{name}}"}
+	r.Properties = []*Type{
+		{Name: "enabled", Type: "Boolean", DefaultValue: true},
+		{Name: "password", Type: "String", IgnoreRead: true, Sensitive: true},
+		{Name: "tier", Type: "String", DefaultFromApi: true},
+		{Name: "zone", Type: "String", DefaultFromApi: true, IgnoreRead: true},
+		{Name: "region", Type: "String", DefaultFromApi: true, IgnoreRead: true},
+		{Name: "network", Type: "String", DefaultFromApi: true, IgnoreRead: true, CustomFlatten: "templates/terraform/custom_flatten/network.go.tmpl"},
+		{
+			Name: "users",
+			Type: "Array",
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "name", Type: "String"},
+					{Name: "password", Type: "String", IgnoreRead: true},
+					{Name: "token", Type: "String", IgnoreRead: true, SendEmptyValue: true},
+				},
+			},
+		},
+		{
+			Name:          "accounts",
+			Type:          "Array",
+			CustomFlatten: "templates/terraform/custom_flatten/accounts.go.tmpl",
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "password", Type: "String", IgnoreRead: true},
+				},
+			},
+		},
+		{
+			Name: "status",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "state", Type: "String", Output: true},
+			},
+		},
+	}
+	r.SetDefault(&Product{Name: "test"})
+
+	var got []string
+	for _, p := range r.PotentialPerpetualDiffFields() {
+		got = append(got, p.PathPattern())
+	}
+	if expected := []string{"region", "users.*.password"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestResourceLabelsFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(derived bool) *Resource {
+		r := &Resource{Name: "address", BaseUrl: "projects/{{project}}/regions/{{region}}/addresses"}
+		labels := &Type{Name: "labels", Type: "KeyValueLabels"}
+		fingerprint := &Type{Name: "labelFingerprint", Type: "Fingerprint", Output: true}
+		if derived {
+			r.LabelsFingerprint = "labelFingerprint"
+		} else {
+			for _, p := range []*Type{labels, fingerprint} {
+				p.UpdateUrl = "projects/{{project}}/regions/{{region}}/addresses/{{name}}/setLabels"
+				p.UpdateVerb = "POST"
+			}
+		}
+		r.Properties = []*Type{{Name: "name", Type: "String"}, labels, fingerprint}
+		r.Properties = r.AddLabelsRelatedFields(r.PropertiesWithExcluded(), nil)
+		r.SetDefault(&Product{Name: "compute"})
+		return r
+	}
+
+	manual := newResource(false)
+	derived := newResource(true)
+
+	if got, expected := derived.PropertiesByCustomUpdateGroups(), manual.PropertiesByCustomUpdateGroups(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected update groups %v, got %v", expected, got)
+	}
+	expected := []UpdateGroup{{UpdateUrl: "projects/{{project}}/regions/{{region}}/addresses/{{name}}/setLabels", UpdateVerb: "POST"}}
+	if got := derived.PropertiesByCustomUpdateGroups(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected update groups %v, got %v", expected, got)
+	}
+	var updated []string
+	for _, p := range derived.PropertiesByCustomUpdate(derived.AllUserProperties())[expected[0]] {
+		updated = append(updated, p.Name)
+	}
+	if !reflect.DeepEqual(updated, []string{"labelFingerprint", "effectiveLabels"}) {
+		t.Errorf("expected the fingerprint to be updated with the labels, got %v", updated)
+	}
+
+	t.Run("not a fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		r := newResource(true)
+		r.LabelsFingerprint = "name"
+		assertFatal(t, func() { r.validateLabelsFingerprint() })
+	})
+}
+
+func TestResourceNestedLabelsProperties(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(flatten bool) *Resource {
+		r := &Resource{Name: "cluster", Immutable: true}
+		spec := &Type{Name: "spec", Type: "NestedObject", FlattenObject: flatten, Properties: []*Type{
+			{Name: "labels", Type: "KeyValueLabels"},
+			{Name: "size", Type: "Integer"},
+		}}
+		r.Properties = []*Type{{Name: "name", Type: "String"}, spec}
+		r.Properties = r.AddLabelsRelatedFields(r.PropertiesWithExcluded(), nil)
+		r.SetDefault(&Product{Name: "test"})
+		return r
+	}
+
+	r := newResource(false)
+	spec := r.Properties[1]
+	var lineages []string
+	for _, p := range spec.Properties {
+		lineages = append(lineages, p.TerraformLineage())
+	}
+	if expected := []string{"spec.0.labels", "spec.0.size", "spec.0.terraform_labels", "spec.0.effective_labels"}; !reflect.DeepEqual(lineages, expected) {
+		t.Errorf("expected lineages %v, got %v", expected, lineages)
+	}
+	nested := r.NestedLabelsProperties()
+	if len(nested) != 1 || nested[0].ParentMetadata.TerraformLineage() != "spec" {
+		t.Errorf("expected the labels in spec to be set in a CustomizeDiff, got %v", nested)
+	}
+	if len(r.CustomDiff) != 0 {
+		t.Errorf("expected no root labels CustomizeDiff, got %v", r.CustomDiff)
+	}
+	r.Properties[1].Properties[0].validateLabelsField()
+
+	flattened := newResource(true)
+	labels := flattened.Properties[1].Properties[0]
+	if labels.TerraformLineage() != "labels" {
+		t.Errorf("expected flattened labels at the root, got %s", labels.TerraformLineage())
+	}
+	if labels.IsForceNew() {
+		t.Errorf("expected flattened labels not to force replacement")
+	}
+	if got := flattened.NestedLabelsProperties(); len(got) != 0 {
+		t.Errorf("expected flattened labels to use the root CustomizeDiff, got %v", got)
+	}
+	if !reflect.DeepEqual(flattened.CustomDiff, []string{"tpgresource.SetLabelsDiff"}) {
+		t.Errorf("expected the root labels CustomizeDiff, got %v", flattened.CustomDiff)
+	}
+
+	t.Run("top-level block that isn't computed", func(t *testing.T) {
+		t.Parallel()
+
+		assertFatal(t, func() { newResource(false).validateNestedLabels() })
+
+		r := newResource(false)
+		r.Properties[1].DefaultFromApi = true
+		r.validateNestedLabels()
+	})
+
+	t.Run("labels in an array", func(t *testing.T) {
+		t.Parallel()
+
+		r := &Resource{Name: "cluster"}
+		pools := &Type{Name: "pools", Type: "Array", ItemType: &Type{Type: "NestedObject", Properties: []*Type{
+			{Name: "labels", Type: "KeyValueLabels"},
+		}}}
+		r.Properties = []*Type{pools}
+		r.SetDefault(&Product{Name: "test"})
+		assertFatal(t, func() { pools.ItemType.Properties[0].validateLabelsField() })
+	})
+}
+
+func TestResourceServerGeneratedIdField(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(props ...*Type) *Resource {
+		r := &Resource{Name: "cluster", BaseUrl: "projects/{{project}}/clusters"}
+		r.Properties = append([]*Type{{Name: "name", Type: "String"}}, props...)
+		r.SetDefault(&Product{Name: "test"})
+		return r
+	}
+
+	r := newResource(&Type{Name: "uid", Type: "String", Output: true, ServerGeneratedId: true})
+	if f := r.ServerGeneratedIdField(); f == nil || f.Name != "uid" {
+		t.Fatalf("expected the uid field, got %v", f)
+	}
+	if got := r.FirstIdentityProp().Name; got != "uid" {
+		t.Errorf("expected uid to identify the resource, got %s", got)
+	}
+	if expected := "projects/{{project}}/clusters/{{uid}}"; r.IdFormat != expected {
+		t.Errorf("expected id format %s, got %s", expected, r.IdFormat)
+	}
+	if got := r.ImportIdFormatsFromResource()[0]; got != "projects/{{project}}/clusters/{{uid}}" {
+		t.Errorf("expected the import format to use uid, got %s", got)
+	}
+	r.validateServerGeneratedId()
+
+	r = newResource()
+	if f := r.ServerGeneratedIdField(); f != nil {
+		t.Errorf("expected no server generated id, got %s", f.Name)
+	}
+	if got := r.FirstIdentityProp().Name; got != "name" {
+		t.Errorf("expected name to identify the resource, got %s", got)
+	}
+
+	cases := []struct {
+		description string
+		props       []*Type
+	}{
+		{
+			description: "two fields",
+			props: []*Type{
+				{Name: "uid", Type: "String", Output: true, ServerGeneratedId: true},
+				{Name: "id", Type: "Integer", Output: true, ServerGeneratedId: true},
+			},
+		},
+		{
+			description: "not output",
+			props:       []*Type{{Name: "uid", Type: "String", ServerGeneratedId: true}},
+		},
+		{
+			description: "nested",
+			props: []*Type{{Name: "status", Type: "NestedObject", Output: true, Properties: []*Type{
+				{Name: "uid", Type: "String", Output: true, ServerGeneratedId: true},
+			}}},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := newResource(tc.props...)
+			assertFatal(t, func() { r.validateServerGeneratedId() })
+		})
+	}
+}
+
+func TestResourceCreateOnlyReadable(t *testing.T) {
+	t.Parallel()
+
+	for _, verb := range []string{"PUT", "PATCH"} {
+		verb := verb
+		t.Run(verb, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "instance", UpdateVerb: verb}
+			r.Properties = []*Type{
+				{Name: "name", Type: "String"},
+				{Name: "bootImage", Type: "String", CreateOnlyReadable: true},
+			}
+			r.SetDefault(&Product{Name: "test"})
+			bootImage := r.Properties[1]
+
+			if !bootImage.IsForceNew() || bootImage.IsUpdatable() {
+				t.Errorf("expected bootImage to force replacement")
+			}
+			if !r.IsSettableProperty(bootImage) {
+				t.Errorf("expected bootImage to be sent on create")
+			}
+			if slices.Contains(r.UpdateBodyProperties(), bootImage) {
+				t.Errorf("expected bootImage not to be sent on update")
+			}
+			if !slices.Contains(r.ReadProperties(), bootImage) {
+				t.Errorf("expected bootImage to be read")
+			}
+			bootImage.validateCreateOnlyReadable(r.Name)
+		})
+	}
+
+	cases := []struct {
+		description string
+		prop        *Type
+	}{
+		{
+			description: "output",
+			prop:        &Type{Name: "bootImage", Type: "String", CreateOnlyReadable: true, Output: true},
+		},
+		{
+			description: "ignore_read",
+			prop:        &Type{Name: "bootImage", Type: "String", CreateOnlyReadable: true, IgnoreRead: true},
+		},
+		{
+			description: "update_url",
+			prop:        &Type{Name: "bootImage", Type: "String", CreateOnlyReadable: true, UpdateUrl: "instances/{{name}}:setBootImage"},
+		},
+		{
+			description: "nested",
+			prop: &Type{Name: "boot", Type: "NestedObject", Properties: []*Type{
+				{Name: "image", Type: "String", CreateOnlyReadable: true},
+			}},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "instance", Properties: []*Type{tc.prop}}
+			r.SetDefault(&Product{Name: "test"})
+			assertFatal(t, func() {
+				for _, p := range r.AllNestedProperties(r.RootProperties()) {
+					p.validateCreateOnlyReadable(r.Name)
+				}
+			})
+		})
+	}
+}
+
+func TestResourceCustomizeDiffContributors(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "instance", BaseUrl: "instances"}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String"},
+		{Name: "machineType", Type: "String", ForceNewIfSet: true},
+		{Name: "description", Type: "String"},
+	}
+	r.SetDefault(&Product{Name: "test"})
+
+	var names []string
+	for _, p := range r.CustomizeDiffContributors() {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"machineType"}) {
+		t.Errorf("expected machineType to contribute to the CustomizeDiff, got %v", names)
+	}
+	if !r.RequiresCustomizeDiff() {
+		t.Errorf("expected the resource to require a CustomizeDiff")
+	}
+
+	r.Properties[1].ForceNewIfSet = false
+	if got := r.CustomizeDiffContributors(); len(got) != 0 {
+		t.Errorf("expected no contributors, got %v", got)
+	}
+	if r.RequiresCustomizeDiff() {
+		t.Errorf("expected the resource not to require a CustomizeDiff")
+	}
+
+	r.BaseUrl = "projects/{{project}}/instances"
+	if !r.RequiresCustomizeDiff() {
+		t.Errorf("expected a resource with a project to require a CustomizeDiff")
+	}
+	r.ExcludeDefaultCdiff = true
+	if r.RequiresCustomizeDiff() {
+		t.Errorf("expected `exclude_default_cdiff` to skip the CustomizeDiff")
+	}
+}
+
+func TestResourceRequestBodyKeys(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "topic", BaseUrl: "projects/{{project}}/topics"}
+	r.Parameters = []*Type{{Name: "project", Type: "String", UrlParamOnly: true}}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String"},
+		{Name: "createTime", Type: "String", Output: true},
+		{Name: "labels", Type: "KeyValueLabels"},
+		{Name: "localToggle", Type: "Boolean", ClientSide: true},
+		{Name: "messageStoragePolicy", Type: "NestedObject", Properties: []*Type{
+			{Name: "regions", ApiName: "allowedPersistenceRegions", Type: "Array", ItemType: &Type{Type: "String"}},
+			{Name: "state", Type: "String", Output: true},
+		}},
+		{Name: "rules", Type: "Array", ItemType: &Type{Type: "NestedObject", Properties: []*Type{
+			{Name: "filter", Type: "String"},
+		}}},
+	}
+	r.VirtualFields = []*Type{{Name: "deletion_protection", Type: "Boolean"}}
+	r.Properties = r.AddLabelsRelatedFields(r.PropertiesWithExcluded(), nil)
+	r.SetDefault(&Product{Name: "test"})
+
+	expected := []string{
+		"name",
+		"messageStoragePolicy",
+		"messageStoragePolicy.allowedPersistenceRegions",
+		"rules",
+		"rules.filter",
+		"labels",
+	}
+	if got := r.RequestBodyKeys(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected request body keys %v, got %v", expected, got)
+	}
+
+	if got := r.Properties[1].RequestBodyKeys(); got != nil {
+		t.Errorf("expected no keys for an output field, got %v", got)
+	}
+}
@@ -16,11 +16,16 @@ package api
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -45,13 +50,33 @@ type Type struct {
 	//
 	Description string `yaml:"description,omitempty"`
 
+	// An example value for the field, shown in the generated documentation.
+	// It isn't used by code generation. Array examples are comma-separated.
+	Example string `yaml:"example,omitempty"`
+
 	Exclude bool `yaml:"exclude,omitempty"`
 
+	// Why the field is excluded. Not used by code generation, but listed in
+	// the resource's ExcludedFields.
+	ExcludeReason string `yaml:"exclude_reason,omitempty"`
+
+	// The generated artifacts this property is included in. One of
+	// "resource", "datasource" or "both". Defaults to "both".
+	// Unlike `exclude`, which drops the property everywhere, this only drops
+	// it from the artifacts it isn't scoped to.
+	Scope string `yaml:"scope,omitempty"`
+
 	// Add a deprecation message for a field that's been deprecated in the API
 	// use the YAML chomping folding indicator (>-) if this is a multiline
 	// string, as providers expect a single-line one w/o a newline.
 	DeprecationMessage string `yaml:"deprecation_message,omitempty"`
 
+	// Former Terraform names of a renamed field. Each alias is generated as a
+	// deprecated field that is copied into this one in a CustomizeDiff, so
+	// configurations using either name keep working during the transition.
+	// Top-level, optional fields only. The field becomes Computed+Optional.
+	Aliases []string `yaml:"aliases,omitempty"`
+
 	// Add a removed message for fields no longer supported in the API. This should
 	// be used for fields supported in one version but have been removed from
 	// a different version.
@@ -69,21 +94,66 @@ type Type struct {
 	// behavior.
 	Immutable bool `yaml:"immutable,omitempty"`
 
-	// Indicates that this field is client-side only (aka virtual.)
+	// If true, the field is only accepted by the API on create, but is
+	// returned on read. Changes recreate the resource, the field is left out
+	// of the update request, and it's still read back so drift is shown.
+	// Only top-level fields can set it.
+	CreateOnlyReadable bool `yaml:"create_only_readable,omitempty"`
+
+	// If true, the field can be set in place when it was previously unset,
+	// but changing or removing a set value recreates the resource. This is
+	// done in a CustomizeDiff rather than by the schema's ForceNew.
+	ForceNewIfSet bool `yaml:"force_new_if_set,omitempty"`
+
+	// Indicates that this field is client-side only (aka virtual.) It's never
+	// sent in a request or read from a response, but still takes part in the
+	// diff, and is recreated on change if `immutable` is set.
 	ClientSide bool `yaml:"client_side,omitempty"`
 
 	// url_param_only will not send the field in the resource body and will
 	// not attempt to read the field from the API response.
-	// NOTE - this doesn't work for nested fields
+	// NOTE - this doesn't work for nested fields, other than the children of
+	// an `addressing_only` NestedObject.
 	UrlParamOnly bool `yaml:"url_param_only,omitempty"`
 
+	// [Optional] If true, the NestedObject groups identity fields that are
+	// only used to build URLs and the import ID, eg. {project, location, name}.
+	// The object and its children are treated as url_param_only, and the
+	// children are referenced in URLs as {{object.field}}.
+	AddressingOnly bool `yaml:"addressing_only,omitempty"`
+
 	// For nested fields, this only applies within the parent.
 	// For example, an optional parent can contain a required child.
 	Required bool `yaml:"required,omitempty"`
 
+	// [Optional] If set, the field is only required when generating at or
+	// above this version, and optional otherwise.
+	RequiredVersion string `yaml:"required_version,omitempty"`
+
+	// [Optional] The name of a sibling field whose value this field takes
+	// when it's left empty, eg. a display name defaulting to the name. The
+	// value is copied in a CustomizeDiff, and the field is Computed+Optional.
+	DefaultFromField string `yaml:"default_from_field,omitempty"`
+
+	// [Optional] The provider-level attribute, one of `project`, `region` or
+	// `zone`, whose configured value the field's expander sends when the
+	// field is left empty. The field is Computed+Optional.
+	DefaultFromProvider string `yaml:"default_from_provider,omitempty"`
+
+	// [Optional] Marks the top-level Output field holding the identifier the
+	// server assigns on create, such as a numeric `uid`. The resource's id,
+	// self link and import formats use it in place of `name`. At most one
+	// field per resource may set it.
+	ServerGeneratedId bool `yaml:"server_generated_id,omitempty"`
+
 	// Additional query Parameters to append to GET calls.
 	ReadQueryParams string `yaml:"read_query_params,omitempty"`
 
+	// [Optional] Whether the API can filter on this field server-side, so
+	// that list-style data sources can send it as a query parameter rather
+	// than filtering the results client-side. Primitive fields only.
+	Filterable bool `yaml:"filterable,omitempty"`
+
 	UpdateVerb string `yaml:"update_verb,omitempty"`
 
 	UpdateUrl string `yaml:"update_url,omitempty"`
@@ -107,6 +177,16 @@ type Type struct {
 	// "ForceSendFields" concepts in the autogenerated API clients.
 	SendEmptyValue bool `yaml:"send_empty_value,omitempty"`
 
+	// [Optional] The name of a Boolean sibling field. The empty value of this
+	// field is included in requests, as with send_empty_value, only while
+	// the sibling is set to true, eg. to explicitly clear a server value.
+	SendEmptyValueWhen string `yaml:"send_empty_value_when,omitempty"`
+
+	// [Optional] If true, removing the value of this field sends an explicit
+	// null in the update request, as the API ignores omitted fields. This
+	// corresponds to the "NullFields" concept in the autogenerated API clients.
+	ClearWithNull bool `yaml:"clear_with_null,omitempty"`
+
 	// [Optional] If true, empty nested objects are sent to / read from the
 	// API instead of flattened to null.
 	// The difference between this and send_empty_value is that send_empty_value
@@ -117,10 +197,20 @@ type Type struct {
 	// empty map[string]interface{} like we'd expect.
 	AllowEmptyObject bool `yaml:"allow_empty_object,omitempty"`
 
+	// [Optional] Boolean, Integer and Double only. If true, the value is
+	// expanded as a pointer that is nil when the field is unset, so an
+	// explicitly configured zero value (eg. false) is sent to the API while
+	// an unset field is omitted.
+	Nullable bool `yaml:"nullable,omitempty"`
+
 	MinVersion string `yaml:"min_version,omitempty"`
 
 	ExactVersion string `yaml:"exact_version,omitempty"`
 
+	// Like `exact_version`, for fields that only exist in a discontiguous set
+	// of versions. The field is only included when generating one of them.
+	ExactVersions []string `yaml:"exact_versions,omitempty"`
+
 	// A list of properties that conflict with this property. Uses the "lineage"
 	// field to identify the property eg: parent.meta.label.foo
 	Conflicts []string `yaml:"conflicts,omitempty"`
@@ -134,15 +224,42 @@ type Type struct {
 	// A list of properties that are required to be set together.
 	RequiredWith []string `yaml:"required_with,omitempty"`
 
+	// NestedObject only. Groups of child property names, declared once on the
+	// parent instead of on every child. Each group is expanded into the
+	// exactly_one_of, at_least_one_of or required_with list of its members.
+	ExactlyOneOfGroups [][]string `yaml:"exactly_one_of_groups,omitempty"`
+	AtLeastOneOfGroups [][]string `yaml:"at_least_one_of_groups,omitempty"`
+	RequiredWithGroups [][]string `yaml:"required_with_groups,omitempty"`
+
 	// Can only be overridden - we should never set this ourselves.
 	NewType string `yaml:"-"`
 
+	// 1-based position of the property within its source YAML list, recorded
+	// when the YAML is unmarshalled. Properties are returned in this order; 0
+	// means unrecorded, e.g. for generated fields.
+	SourceIndex int `yaml:"-"`
+
 	Properties []*Type `yaml:"properties,omitempty"`
 
 	EnumValues []string `yaml:"enum_values,omitempty"`
 
+	// The package-qualified name of an exported Go []string holding the
+	// values of the enum, eg. verify.SomeSharedValues. Used instead of
+	// enum_values for large value lists shared across resources, which are
+	// then referenced rather than inlined by validation and docs.
+	EnumValuesFrom string `yaml:"enum_values_from,omitempty"`
+
 	ExcludeDocsValues bool `yaml:"exclude_docs_values,omitempty"`
 
+	// [Optional] If true, the possible values aren't appended to the docs
+	// when the description already mentions every one of them.
+	AutoEnumDocs bool `yaml:"auto_enum_docs,omitempty"`
+
+	// [Optional] Enum values deprecated by the API, mapped to a message
+	// explaining what to use instead. Deprecated values are still accepted,
+	// but configuring one shows a warning.
+	DeprecatedEnumValues map[string]string `yaml:"deprecated_enum_values,omitempty"`
+
 	// ====================
 	// Array Fields
 	// ====================
@@ -152,6 +269,22 @@ type Type struct {
 	// Adds a ValidateFunc to the item schema
 	ItemValidation resource.Validation `yaml:"item_validation,omitempty"`
 
+	// Array of Integer or Double only. Inclusive bounds on the value of each
+	// item, validated with validation.IntBetween or validation.FloatBetween
+	// unless `item_validation` is set. Either bound may be left unset.
+	ItemMinimum string `yaml:"item_minimum,omitempty"`
+	ItemMaximum string `yaml:"item_maximum,omitempty"`
+
+	// Array of NestedObject only. If true, the `exactly_one_of` groups among
+	// the fields of the item object, listed by their element-relative names,
+	// are checked within each element by a CustomizeDiff.
+	ExactlyOneOfPerItem bool `yaml:"exactly_one_of_per_item,omitempty"`
+
+	// Array of primitives only. If true, configuring the same item twice is
+	// rejected at plan time by a CustomizeDiff, for ordered lists whose API
+	// requires unique items.
+	UniqueItems bool `yaml:"unique_items,omitempty"`
+
 	ParentName string `yaml:"parent_name,omitempty"`
 
 	// ====================
@@ -167,6 +300,17 @@ type Type struct {
 	// Adds a DiffSuppressFunc to the schema
 	DiffSuppressFunc string `yaml:"diff_suppress_func,omitempty"`
 
+	// Runs `diff_suppress_func` in a resource-level CustomizeDiff instead of
+	// on the schema, so that it can read the rest of the resource's state.
+	// The function must be a tpgresource.ResourceDiffSuppressFunc, and the
+	// field must set `default_from_api`, as only computed keys can have their
+	// diff cleared.
+	DiffSuppressInCustomizeDiff bool `yaml:"diff_suppress_in_customize_diff,omitempty"`
+
+	// Integer or Double only. Adds a DiffSuppressFunc that compares the parsed
+	// numbers rather than their string forms, so that `3` and `3.0` are equal.
+	SuppressNumericDiff bool `yaml:"suppress_numeric_diff,omitempty"`
+
 	StateFunc string `yaml:"state_func,omitempty"` // Adds a StateFunc to the schema
 
 	Sensitive bool `yaml:"sensitive,omitempty"` // Adds `Sensitive: true` to the schema
@@ -178,7 +322,19 @@ type Type struct {
 	// Adds a ValidateFunc to the schema
 	Validation resource.Validation `yaml:"validation,omitempty"`
 
+	// String only. Bounds on the length of the value, validated with
+	// validation.StringLenBetween unless `validation` is set.
+	MinLength int `yaml:"min_length,omitempty"`
+	MaxLength int `yaml:"max_length,omitempty"`
+
+	// String only. A regex the value must match, validated with
+	// validation.StringMatch unless `validation` is set. PatternMessage is
+	// the optional error message shown when it doesn't match.
+	Pattern        string `yaml:"pattern,omitempty"`
+	PatternMessage string `yaml:"pattern_message,omitempty"`
+
 	// Indicates that this is an Array that should have Set diff semantics.
+	// It is generated as a schema.TypeSet, like an `is_set` Array.
 	UnorderedList bool `yaml:"unordered_list,omitempty"`
 
 	IsSet bool `yaml:"is_set,omitempty"` // Uses a Set instead of an Array
@@ -188,6 +344,12 @@ type Type struct {
 	// schema.HashSchema are used.
 	SetHashFunc string `yaml:"set_hash_func,omitempty"`
 
+	// is_set Arrays of NestedObjects only. An expression over the item's
+	// fields, referenced by their Terraform names, ie `{{.name}}-{{.region}}`,
+	// compiled into a hash function for the set as an alternative to
+	// set_hash_func.
+	SetHashExpr string `yaml:"set_hash_expr,omitempty"`
+
 	// if true, then we get the default value from the Google API if no value
 	// is set in the terraform configuration for this field.
 	// It translates to setting the field to Computed & Optional in the schema.
@@ -196,6 +358,12 @@ type Type struct {
 	// behavior.
 	DefaultFromApi bool `yaml:"default_from_api,omitempty"`
 
+	// Array, Map and NestedObject only. Implies `default_from_api`, and also
+	// applies it to every nested field that isn't required, output-only or
+	// defaulted, so that the whole block is computed when omitted from the
+	// configuration.
+	DefaultFromApiRecursive bool `yaml:"default_from_api_recursive,omitempty"`
+
 	// https://github.com/hashicorp/terraform/pull/20837
 	// Apply a ConfigMode of SchemaConfigModeAttr to the field.
 	// This should be avoided for new fields, and only used with old ones.
@@ -284,6 +452,18 @@ type Type struct {
 	// just as they are in the standard flattener template.
 	CustomFlatten string `yaml:"custom_flatten,omitempty"`
 
+	// A dotted path to follow from the field's value in the API response to
+	// reach the value to read, for APIs that wrap the value in extra objects
+	// on read only, ie `value` for `{"value": "x"}`. Used by the default
+	// flattener, so it can't be combined with `custom_flatten`.
+	ReadPath string `yaml:"read_path,omitempty"`
+
+	// The shapes the field had in older schema versions, for fields whose
+	// type changed in a schema version bump. Used to scaffold the
+	// StateUpgraders that migrate state from those versions. Top-level
+	// fields only.
+	SchemaVersionChanges []SchemaVersionChange `yaml:"schema_version_changes,omitempty"`
+
 	ResourceMetadata *Resource `yaml:"resource_metadata,omitempty"`
 
 	ParentMetadata *Type `yaml:"parent_metadata,omitempty"` // is nil for top-level properties
@@ -293,17 +473,84 @@ type Type struct {
 	Prefix string `yaml:"prefix,omitempty"`
 }
 
+// The shape of a field in an older schema version of its resource.
+type SchemaVersionChange struct {
+	// The schema version the field had this shape in
+	Version int `yaml:"version"`
+
+	// The type of the field in that version, eg. String
+	Type string `yaml:"type"`
+}
+
 const MAX_NAME = 20
 
+// Properties nested deeper than this produce very large generated code and
+// slow plans. Validate warns about them, or fails when
+// FailOnMaxPropertyDepth is set. Both are set from the -max-property-depth
+// and -fail-on-max-property-depth flags.
+var MaxPropertyDepth = 7
+
+var FailOnMaxPropertyDepth = false
+
+var enumValuesFromRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*\.[A-Z][A-Za-z0-9_]*$`)
+
+var setHashExprFieldRegex = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+var readPathRegex = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// Matches valid Terraform field names, such as a Map's key_name
+var terraformFieldNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Matches an `api_field_name_prefix` or `api_field_name_suffix`.
+var apiFieldNameAffixRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+const (
+	SCOPE_RESOURCE   = "resource"
+	SCOPE_DATASOURCE = "datasource"
+	SCOPE_BOTH       = "both"
+)
+
+func (t *Type) UnmarshalYAML(unmarshal func(any) error) error {
+	type typeAlias Type
+	aliasObj := (*typeAlias)(t)
+
+	err := unmarshal(aliasObj)
+	if err != nil {
+		return err
+	}
+
+	recordSourceIndexes(t.Properties)
+
+	return nil
+}
+
 func (t *Type) SetDefault(r *Resource) {
 	t.ResourceMetadata = r
 	if t.UpdateVerb == "" {
 		t.UpdateVerb = t.ResourceMetadata.UpdateVerb
 	}
 
+	if t.ApiName == "" {
+		t.ApiName = t.Name
+		if !t.UrlParamOnly {
+			t.ApiName = r.ApiFieldName(t.Name)
+		}
+	}
+
+	if t.DefaultFromApiRecursive {
+		t.propagateDefaultFromApi()
+	}
+
 	switch {
 	case t.IsA("Array"):
+		// unordered lists are generated as sets
+		if t.UnorderedList {
+			t.IsSet = true
+		}
 		t.ItemType.Name = t.Name
+		if t.ItemType.ApiName == "" {
+			t.ItemType.ApiName = t.ApiName
+		}
 		t.ItemType.ParentName = t.Name
 		t.ItemType.ParentMetadata = t
 		t.ItemType.SetDefault(r)
@@ -325,6 +572,23 @@ func (t *Type) SetDefault(r *Resource) {
 
 		for _, p := range t.Properties {
 			p.ParentMetadata = t
+		}
+
+		t.expandGroups()
+
+		if t.FlattenObject {
+			t.promoteToFlattenedChildren()
+		}
+
+		if t.AddressingOnly {
+			t.UrlParamOnly = true
+			for _, c := range t.Properties {
+				c.UrlParamOnly = true
+			}
+		}
+
+		recordGeneratedIndexes(t.Properties)
+		for _, p := range t.Properties {
 			p.SetDefault(r)
 		}
 	case t.IsA("ResourceRef"):
@@ -342,32 +606,206 @@ func (t *Type) SetDefault(r *Resource) {
 		t.Output = true
 	default:
 	}
-
-	if t.ApiName == "" {
-		t.ApiName = t.Name
-	}
 }
 
 func (t *Type) Validate(rName string) {
 	if t.Name == "" {
-		log.Fatalf("Missing `name` for proprty with type %s in resource %s", t.Type, rName)
+		validationFailure("Missing `name` for proprty with type %s in resource %s", t.Type, rName)
 	}
 
 	if t.Output && t.Required {
-		log.Fatalf("Property %s cannot be output and required at the same time in resource %s.", t.Name, rName)
+		validationFailure("Property %s cannot be output and required at the same time in resource %s.", t.Name, rName)
+	}
+
+	if t.RequiredVersion != "" {
+		if t.Required || t.Output {
+			validationFailure("Property %s in resource %s cannot set `required_version` with `required` or `output`.", t.Lineage(), rName)
+		}
+		if !slices.Contains(product.ORDER, t.RequiredVersion) {
+			validationFailure("Property %s in resource %s has an unknown `required_version` %s, should be one of %v.", t.Lineage(), rName, t.RequiredVersion, product.ORDER)
+		}
 	}
 
 	if t.DefaultFromApi && t.DefaultValue != nil {
-		log.Fatalf("'default_value' and 'default_from_api' cannot be both set in resource %s", rName)
+		validationFailure("'default_value' and 'default_from_api' cannot be both set in resource %s", rName)
+	}
+
+	if t.SendEmptyValue {
+		if t.Output {
+			validationFailure("Property %s in resource %s cannot set `send_empty_value` on an output field, which is never sent.", t.Lineage(), rName)
+		}
+		if t.UrlParamOnly {
+			validationFailure("Property %s in resource %s cannot set `send_empty_value` on a `url_param_only` field, which is not sent in the request body.", t.Lineage(), rName)
+		}
+	}
+
+	t.validateAddressingObject(rName)
+
+	t.validateImmutable(rName)
+
+	t.validateCreateOnlyReadable(rName)
+
+	t.validateClientSide(rName)
+
+	if t.ParentMetadata == nil {
+		t.validateDepth(rName)
+	}
+
+	t.validateGroups(rName)
+
+	t.validateLength(rName)
+
+	t.validatePattern(rName)
+
+	t.validateItemRange(rName)
+
+	t.validateMapFields(rName)
+
+	if t.DefaultFromApiRecursive && !(t.IsA("Array") || t.IsA("Map") || t.IsA("NestedObject")) {
+		validationFailure("Property %s in resource %s can only set `default_from_api_recursive` if it is an Array, Map or NestedObject.", t.Lineage(), rName)
+	}
+
+	if t.ReadPath != "" {
+		if t.CustomFlatten != "" {
+			validationFailure("Property %s in resource %s cannot set both `read_path` and `custom_flatten`.", t.Lineage(), rName)
+		}
+		if !readPathRegex.MatchString(t.ReadPath) {
+			validationFailure("`read_path` %s of property %s in resource %s should be a dotted path of API field names.", t.ReadPath, t.Lineage(), rName)
+		}
+	}
+
+	if t.ForceNewIfSet {
+		if t.Immutable {
+			validationFailure("Property %s in resource %s cannot set both `force_new_if_set` and `immutable`.", t.Lineage(), rName)
+		}
+		if t.Output {
+			validationFailure("Property %s in resource %s cannot set `force_new_if_set` on an output field.", t.Lineage(), rName)
+		}
+		// the CustomizeDiff addresses the field by its path, which isn't known
+		// for array elements
+		if t.inArray() {
+			validationFailure("Property %s in resource %s cannot set `force_new_if_set` inside an Array.", t.Lineage(), rName)
+		}
+	}
+
+	t.validateSetHashExpr(rName)
+
+	t.validateDefaultFromField(rName)
+
+	t.validateDefaultFromProvider(rName)
+
+	if t.ExactlyOneOfPerItem && !(t.IsA("Array") && t.ItemType != nil && t.ItemType.IsA("NestedObject")) {
+		validationFailure("Property %s in resource %s can only set `exactly_one_of_per_item` if it is an Array of NestedObject.", t.Lineage(), rName)
+	}
+
+	if t.UniqueItems {
+		if !t.IsA("Array") || t.ItemType == nil || !slices.Contains(primitiveTypes, t.ItemType.Type) {
+			validationFailure("Property %s in resource %s can only set `unique_items` if it is an Array of primitives.", t.Lineage(), rName)
+		}
+		if t.IsSet || t.Output {
+			validationFailure("Property %s in resource %s cannot set `unique_items` with `is_set` or `output`.", t.Lineage(), rName)
+		}
+	}
+
+	t.validateSendEmptyValueWhen(rName)
+
+	t.validateClearWithNull(rName)
+
+	t.validateDiffSuppressInCustomizeDiff(rName)
+
+	t.validateSchemaVersionChanges(rName)
+
+	t.validateAliases(rName)
+
+	if t.ExactVersion != "" && len(t.ExactVersions) > 0 {
+		validationFailure("Property %s in resource %s cannot set both `exact_version` and `exact_versions`.", t.Lineage(), rName)
+	}
+
+	if t.Filterable && (t.Output || !slices.Contains(primitiveTypes, t.Type)) {
+		validationFailure("Property %s in resource %s can only set `filterable` if it is a non-output field of one of %v.", t.Lineage(), rName, primitiveTypes)
+	}
+
+	if t.SuppressNumericDiff {
+		if !(t.IsA("Integer") || t.IsA("Double")) {
+			validationFailure("Property %s in resource %s can only set `suppress_numeric_diff` if it is an Integer or Double.", t.Lineage(), rName)
+		}
+		if t.DiffSuppressFunc != "" {
+			validationFailure("Property %s in resource %s cannot set both `suppress_numeric_diff` and `diff_suppress_func`.", t.Lineage(), rName)
+		}
+	}
+
+	if t.Nullable {
+		if !(t.IsA("Boolean") || t.IsA("Integer") || t.IsA("Double")) {
+			validationFailure("Property %s in resource %s can only set `nullable` if it is a Boolean, Integer or Double.", t.Lineage(), rName)
+		}
+		// the configured value is looked up by its path, which isn't known
+		// for array elements
+		if t.inArray() {
+			validationFailure("Property %s in resource %s cannot set `nullable` inside an Array.", t.Lineage(), rName)
+		}
+	}
+
+	if t.SchemaConfigModeAttr {
+		log.Printf("WARNING: Property %s in resource %s sets `schema_config_mode_attr`, which should only be used for existing fields.", t.Lineage(), rName)
+	}
+
+	if t.Exclude && t.ExcludeReason == "" {
+		log.Printf("WARNING: Property %s in resource %s sets `exclude` without an `exclude_reason`.", t.Lineage(), rName)
+	}
+
+	if t.Output && !t.Sensitive && t.looksLikeSecret() {
+		log.Printf("WARNING: Property %s in resource %s is an output field named like a secret, but doesn't set `sensitive`.", t.Lineage(), rName)
+	}
+
+	if t.Example != "" && t.Output {
+		log.Printf("WARNING: Property %s in resource %s sets `example` but is an output field.", t.Lineage(), rName)
+	}
+
+	if len(t.EnumValues) > 0 && t.EnumValuesFrom != "" {
+		validationFailure("Property %s cannot set both `enum_values` and `enum_values_from` in resource %s.", t.Lineage(), rName)
+	}
+
+	if t.EnumValuesFrom != "" && !enumValuesFromRegex.MatchString(t.EnumValuesFrom) {
+		validationFailure("`enum_values_from` %s of property %s in resource %s should be a package-qualified exported name.", t.EnumValuesFrom, t.Lineage(), rName)
+	}
+
+	if len(t.DeprecatedEnumValues) > 0 {
+		if !t.IsA("Enum") {
+			validationFailure("Property %s in resource %s can only set `deprecated_enum_values` if it is an Enum.", t.Lineage(), rName)
+		}
+		values := maps.Keys(t.DeprecatedEnumValues)
+		slices.Sort(values)
+		for _, v := range values {
+			if !slices.Contains(t.EnumValues, v) {
+				validationFailure("Deprecated value %s of property %s in resource %s is not one of its `enum_values`.", v, t.Lineage(), rName)
+			}
+		}
+	}
+
+	if t.IsA("Quantity") && (len(t.EnumValues) > 0 || t.EnumValuesFrom != "") {
+		validationFailure("Property %s in resource %s is a Quantity and cannot set `enum_values`.", t.Lineage(), rName)
+	}
+
+	if !slices.Contains([]string{"", SCOPE_RESOURCE, SCOPE_DATASOURCE, SCOPE_BOTH}, t.Scope) {
+		validationFailure("Invalid `scope` %s for property %s in resource %s, should be one of %s, %s or %s", t.Scope, t.Lineage(), rName, SCOPE_RESOURCE, SCOPE_DATASOURCE, SCOPE_BOTH)
 	}
 
 	t.validateLabelsField()
 
+	if strictValidation {
+		t.validateStrict(rName)
+	}
+
 	switch {
 	case t.IsA("Array"):
-		t.ItemType.Validate(rName)
+		t.validateItemType(rName)
+		if t.ItemType != nil {
+			t.ItemType.Validate(rName)
+		}
 	case t.IsA("Map"):
-		t.ValueType.Validate(rName)
+		if t.ValueType != nil {
+			t.ValueType.Validate(rName)
+		}
 	case t.IsA("NestedObject"):
 		for _, p := range t.Properties {
 			p.Validate(rName)
@@ -376,6 +814,106 @@ func (t *Type) Validate(rName string) {
 	}
 }
 
+// Returns whether the property is a string whose name ends in a word that
+// usually names a secret, such as `sharedSecret` or `accessToken`. Public keys
+// and references to KMS keys aren't secrets.
+func (t Type) looksLikeSecret() bool {
+	if !t.IsA("String") {
+		return false
+	}
+
+	words := strings.Split(google.Underscore(t.Name), "_")
+	if !slices.Contains([]string{"secret", "token", "key"}, words[len(words)-1]) {
+		return false
+	}
+	lineage := strings.Split(strings.ReplaceAll(t.Lineage(), ".", "_"), "_")
+	return !slices.Contains(lineage, "public") && !slices.Contains(words, "kms")
+}
+
+// Returns why the property is likely to show a diff on every plan, or an
+// empty string if neither combination known to cause one is found. Properties
+// with a custom flatten are assumed to handle what they read back.
+func (t Type) perpetualDiffReason() string {
+	if t.CustomFlatten != "" || t.Output || t.UrlParamOnly {
+		return ""
+	}
+
+	customFlattened := false
+	for p := t.ParentMetadata; p != nil; p = p.ParentMetadata {
+		customFlattened = customFlattened || p.CustomFlatten != ""
+	}
+	if customFlattened {
+		return ""
+	}
+
+	// Array items are flattened without the fields that ignore reads, rather
+	// than keeping their configured values
+	if t.IgnoreRead && !t.SendEmptyValue && t.inArray() {
+		return "it sets `ignore_read` without `send_empty_value` within an Array, so the configured value is dropped when the item is read back"
+	}
+
+	// a computed value that is never read back only ever holds the
+	// configured value, not the one chosen by the API. Values in the id,
+	// such as a zone, are read back from it instead.
+	if t.DefaultFromApi && t.IgnoreRead && !t.inResourceId() {
+		return "it is settable and `default_from_api`, but `ignore_read` means the value from the API is never read back"
+	}
+
+	return ""
+}
+
+// Returns whether the property is a top-level field that is part of the
+// resource's id.
+func (t Type) inResourceId() bool {
+	if t.ParentMetadata != nil || t.ResourceMetadata == nil {
+		return false
+	}
+
+	return slices.Contains(t.ResourceMetadata.ExtractIdentifiers(t.ResourceMetadata.GetIdFormat()), google.Underscore(t.Name))
+}
+
+// Runs the checks that are only enforced in strict mode, as existing
+// definitions don't all pass them yet.
+func (t Type) validateStrict(rName string) {
+	if t.IsA("Enum") && len(t.EnumValues) == 0 && t.EnumValuesFrom == "" {
+		validationFailure("Property %s in resource %s is an Enum without `enum_values` or `enum_values_from`.", t.Lineage(), rName)
+	}
+
+	if t.DefaultValue != nil && !t.defaultValueMatchesType() {
+		validationFailure("Property %s in resource %s has a `default_value` of type %T, which doesn't match its type %s.", t.Lineage(), rName, t.DefaultValue, t.Type)
+	}
+
+	if t.IsA("ResourceRef") && (t.Resource == "" || t.Imports == "") {
+		validationFailure("Property %s in resource %s is a ResourceRef without a `resource` and `imports`.", t.Lineage(), rName)
+	}
+
+	// schema.HashSchema hashes every field, so output and defaulted fields
+	// of the items can make the same item hash differently
+	if t.IsObjectSet() && t.SetHashFunction() == "" {
+		validationFailure("Property %s in resource %s is a set of objects without a `set_hash_func` or `set_hash_expr`.", t.Lineage(), rName)
+	}
+}
+
+// Returns whether the `default_value` of the property, as parsed from YAML,
+// has a Go type that matches the property's type. Types without a clear
+// counterpart are not checked.
+func (t Type) defaultValueMatchesType() bool {
+	switch t.DefaultValue.(type) {
+	case string:
+		return t.IsA("String") || t.IsA("Enum") || t.IsA("Time") || t.IsA("ResourceRef") || t.IsA("Quantity")
+	case int:
+		return t.IsA("Integer") || t.IsA("Double")
+	case float64:
+		return t.IsA("Double")
+	case bool:
+		return t.IsA("Boolean")
+	case []interface{}:
+		return t.IsA("Array")
+	}
+
+	return !slices.Contains([]string{"String", "Enum", "Time", "ResourceRef", "Quantity", "Integer", "Double", "Boolean", "Array"}, t.Type)
+}
+
 // TODO rewrite: add validations
 // check :description, required: true
 // check :update_verb, allowed: %i[POST PUT PATCH NONE],
@@ -385,7 +923,77 @@ func (t *Type) Validate(rName string) {
 // check_exactly_one_of
 // check_required_with
 // check the allowed types for Type field
-// check the allowed fields for each type, for example, KeyName is only allowed for Map
+
+// Applies `default_from_api` to the property and its direct children that
+// aren't required, output-only or defaulted, carrying `default_from_api_recursive` down to
+// composite children so that their own SetDefault continues the propagation.
+func (t *Type) propagateDefaultFromApi() {
+	t.DefaultFromApi = true
+
+	var children []*Type
+	switch {
+	case t.IsA("Array"):
+		children = []*Type{t.ItemType}
+	case t.IsA("Map"):
+		children = []*Type{t.ValueType}
+	case t.IsA("NestedObject"):
+		children = t.Properties
+	}
+
+	for _, c := range children {
+		if c == nil || c.Required || c.Output || c.DefaultValue != nil {
+			continue
+		}
+		if c.IsA("Array") || c.IsA("Map") || c.IsA("NestedObject") {
+			c.DefaultFromApiRecursive = true
+		} else {
+			c.DefaultFromApi = true
+		}
+	}
+}
+
+// The types the children of an `addressing_only` NestedObject can have, as
+// their values are substituted into URLs.
+var addressingFieldTypes = []string{"String", "Integer", "Boolean", "Enum", "ResourceRef"}
+
+// Checks that an `addressing_only` NestedObject is a top-level field with only
+// primitive children, so that each child can be referenced in URLs.
+func (t Type) validateAddressingObject(rName string) {
+	if !t.AddressingOnly {
+		return
+	}
+
+	if !t.IsA("NestedObject") {
+		validationFailure("Property %s in resource %s can only set `addressing_only` if it is a NestedObject.", t.Lineage(), rName)
+	}
+	if t.ParentMetadata != nil {
+		validationFailure("Property %s in resource %s is an `addressing_only` NestedObject, which must be a top-level field.", t.Lineage(), rName)
+	}
+	if t.FlattenObject {
+		validationFailure("Property %s in resource %s cannot set both `addressing_only` and `flatten_object`.", t.Lineage(), rName)
+	}
+	for _, c := range t.Properties {
+		if !slices.Contains(addressingFieldTypes, c.Type) {
+			validationFailure("Property %s in resource %s is a %s, but the children of an `addressing_only` NestedObject should be one of %v.", c.Lineage(), rName, c.Type, addressingFieldTypes)
+		}
+	}
+}
+
+// Carries the output and `default_from_api` semantics of a flattened object
+// over to its children, since only the children appear in the schema. Required
+// and defaulted children are left as is.
+func (t *Type) promoteToFlattenedChildren() {
+	for _, c := range t.Properties {
+		if c.Required || c.DefaultValue != nil {
+			continue
+		}
+		if t.Output {
+			c.Output = true
+		} else if t.DefaultFromApi && !c.Output {
+			c.DefaultFromApi = true
+		}
+	}
+}
 
 // Prints a dot notation path to where the field is nested within the parent
 // object. eg: parent.meta.label.foo
@@ -399,6 +1007,31 @@ func (t Type) Lineage() string {
 	return fmt.Sprintf("%s.%s", t.ParentMetadata.Lineage(), google.Underscore(t.Name))
 }
 
+// Prints a dot notation path to where the field is nested within the API
+// request and response objects, using the api_name of each level, eg:
+// metadata.labels. Array items and Map values don't add a level of their own.
+func (t Type) ApiLineage() string {
+	if t.ParentMetadata == nil {
+		return t.ApiName
+	}
+
+	if t.ParentMetadata.IsA("Array") || t.ParentMetadata.IsA("Map") {
+		return t.ParentMetadata.ApiLineage()
+	}
+
+	return fmt.Sprintf("%s.%s", t.ParentMetadata.ApiLineage(), t.ApiName)
+}
+
+// Returns the key of the field within its parent's API request and response
+// object, as found by the ApiLineage walk. The expanders and flatteners build
+// each level of a nested object from these keys.
+func (t Type) ApiKey() string {
+	if t.ParentMetadata == nil {
+		return t.ApiLineage()
+	}
+	return strings.TrimPrefix(t.ApiLineage(), t.ParentMetadata.ApiLineage()+".")
+}
+
 // Prints the access path of the field in the configration eg: metadata.0.labels
 // The only intended purpose is to get the value of the labes field by calling d.Get().
 func (t Type) TerraformLineage() string {
@@ -423,6 +1056,32 @@ func (t Type) EnumValuesToString(quoteSeperator string, addEmpty bool) string {
 	return strings.Join(values, ", ")
 }
 
+// Returns the deprecated enum values and their messages as a Go map literal,
+// sorted by value.
+func (t Type) DeprecatedEnumValuesLiteral() string {
+	values := maps.Keys(t.DeprecatedEnumValues)
+	slices.Sort(values)
+
+	var entries []string
+	for _, v := range values {
+		entries = append(entries, fmt.Sprintf("%q: %q", v, t.DeprecatedEnumValues[v]))
+	}
+	return fmt.Sprintf("map[string]string{%s}", strings.Join(entries, ", "))
+}
+
+// Returns a Go expression for the []string of allowed enum values, referencing
+// enum_values_from instead of inlining the values when it is set.
+func (t Type) EnumValuesSlice(addEmpty bool) string {
+	if t.EnumValuesFrom == "" {
+		return fmt.Sprintf("[]string{%s}", t.EnumValuesToString("\"", addEmpty))
+	}
+
+	if addEmpty && !t.Required {
+		return fmt.Sprintf("append([]string{\"\"}, %s...)", t.EnumValuesFrom)
+	}
+	return t.EnumValuesFrom
+}
+
 func (t Type) TitlelizeProperty() string {
 	return google.Camelize(t.Name, "upper")
 }
@@ -454,6 +1113,18 @@ func (t *Type) GetPrefix() string {
 	return t.Prefix
 }
 
+// Returns the name of the function generated to expand the property, eg.
+// expandPubsubTopicMessageStoragePolicy
+func (t Type) ExpandFunctionName() string {
+	return fmt.Sprintf("expand%s%s", t.GetPrefix(), t.TitlelizeProperty())
+}
+
+// Returns the name of the function generated to flatten the property, eg.
+// flattenPubsubTopicMessageStoragePolicy
+func (t Type) FlattenFunctionName() string {
+	return fmt.Sprintf("flatten%s%s", t.GetPrefix(), t.TitlelizeProperty())
+}
+
 func (t Type) ResourceType() string {
 	r := t.ResourceRef()
 	if r == nil {
@@ -540,7 +1211,8 @@ func (t Type) AtLeastOneOfList() []string {
 // Returns list of properties that needs exactly one of their fields set.
 // func (t *Type) exactly_one_of_list() {
 func (t Type) ExactlyOneOfList() []string {
-	if t.ResourceMetadata == nil {
+	// item-scoped groups are checked in a CustomizeDiff
+	if t.ResourceMetadata == nil || t.ItemScopedExactlyOneOf() != nil {
 		return []string{}
 	}
 
@@ -566,9 +1238,282 @@ func (t Type) RequiredWithList() []string {
 	return t.RequiredWith
 }
 
-func (t Type) Parent() *Type {
-	return t.ParentMetadata
-}
+// Expands the groups declared on a NestedObject into the exactly_one_of,
+// at_least_one_of and required_with lists of its children, in the same form
+// as if they had been listed on each child.
+func (t *Type) expandGroups() {
+	for _, group := range t.ExactlyOneOfGroups {
+		paths := t.groupPaths(group)
+		for _, member := range group {
+			if p := t.groupMember(member); p != nil {
+				p.ExactlyOneOf = appendMissing(p.ExactlyOneOf, paths)
+			}
+		}
+	}
+
+	for _, group := range t.AtLeastOneOfGroups {
+		paths := t.groupPaths(group)
+		for _, member := range group {
+			if p := t.groupMember(member); p != nil {
+				p.AtLeastOneOf = appendMissing(p.AtLeastOneOf, paths)
+			}
+		}
+	}
+
+	// required_with lists every field of the group except the field itself
+	for _, group := range t.RequiredWithGroups {
+		paths := t.groupPaths(group)
+		for _, member := range group {
+			if p := t.groupMember(member); p != nil {
+				others := google.Reject(paths, func(path string) bool {
+					return path == t.groupPath(p)
+				})
+				p.RequiredWith = appendMissing(p.RequiredWith, others)
+			}
+		}
+	}
+}
+
+// Returns the child property named by a group member, which may use either
+// the property name or its underscored form.
+func (t Type) groupMember(member string) *Type {
+	for _, p := range t.Properties {
+		if p.Name == member || google.Underscore(p.Name) == member {
+			return p
+		}
+	}
+	return nil
+}
+
+func (t Type) groupPaths(group []string) []string {
+	var paths []string
+	for _, member := range group {
+		if p := t.groupMember(member); p != nil {
+			paths = append(paths, t.groupPath(p))
+		}
+	}
+	return paths
+}
+
+// Returns the path a group member is listed by. Members of an Array's item
+// object use their element-relative name, as the group applies within each
+// element.
+func (t Type) groupPath(member *Type) string {
+	if t.isArrayItem() {
+		return google.Underscore(member.Name)
+	}
+	return member.TerraformLineage()
+}
+
+// Returns whether the property is the item_type of an Array.
+func (t Type) isArrayItem() bool {
+	return t.ParentMetadata != nil && t.ParentMetadata.IsA("Array")
+}
+
+// Returns the path of the property with "*" in place of the index of each
+// enclosing Array, eg. "rules.*.matches".
+func (t Type) pathPattern() string {
+	p := t.ParentMetadata
+	switch {
+	case p == nil || p.FlattenObject:
+		return google.Underscore(t.Name)
+	case p.isArrayItem():
+		return fmt.Sprintf("%s.*.%s", p.ParentMetadata.pathPattern(), google.Underscore(t.Name))
+	default:
+		return fmt.Sprintf("%s.0.%s", p.pathPattern(), google.Underscore(t.Name))
+	}
+}
+
+// Returns the path of the property for a CustomizeDiff, with "*" in place of
+// the index of each enclosing Array, eg. "rules.*.ports".
+func (t Type) PathPattern() string {
+	return t.pathPattern()
+}
+
+// Returns the element-relative names of the `exactly_one_of` group of a
+// field within an Array's item object, eg. ["glob", "regex"], if every member
+// of the group is a sibling within the item and the Array sets
+// `exactly_one_of_per_item`. The SDK can't check such groups per element, so
+// they're checked in a CustomizeDiff instead.
+func (t Type) ItemScopedExactlyOneOf() []string {
+	if len(t.ExactlyOneOf) == 0 || t.ParentMetadata == nil || !t.ParentMetadata.isArrayItem() {
+		return nil
+	}
+	if !t.ParentMetadata.ParentMetadata.ExactlyOneOfPerItem {
+		return nil
+	}
+
+	var fields []string
+	for _, member := range t.ExactlyOneOf {
+		p := t.ParentMetadata.groupMember(member)
+		if p == nil {
+			return nil
+		}
+		// fields not in this version are left out of the group
+		if !p.Exclude {
+			fields = append(fields, google.Underscore(p.Name))
+		}
+	}
+	return fields
+}
+
+func appendMissing(list, values []string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Returns the distinct fingerprint_name values used by this property and its
+// nested properties, so updates can fetch each fingerprint up front.
+// Excluded properties are skipped.
+func (t Type) FingerprintDependencies() []string {
+	var names []string
+	if t.Exclude {
+		return names
+	}
+
+	if t.FingerprintName != "" {
+		names = append(names, t.FingerprintName)
+	}
+
+	for _, p := range t.NestedProperties() {
+		names = appendMissing(names, p.FingerprintDependencies())
+	}
+	return names
+}
+
+// Returns the sibling field named by `default_from_field`, or nil if there is
+// none.
+func (t Type) DefaultFromFieldSource() *Type {
+	return t.sibling(t.DefaultFromField)
+}
+
+// Returns the sibling field named by `send_empty_value_when`, or nil if there
+// is none.
+func (t Type) SendEmptyValueWhenSource() *Type {
+	return t.sibling(t.SendEmptyValueWhen)
+}
+
+// Returns the field with the given name at the same level as this one, or nil
+// if there is none.
+func (t Type) sibling(name string) *Type {
+	if name == "" {
+		return nil
+	}
+
+	var siblings []*Type
+	if t.ParentMetadata != nil {
+		siblings = t.ParentMetadata.Properties
+	} else if t.ResourceMetadata != nil {
+		siblings = t.ResourceMetadata.AllProperties()
+	}
+	for _, s := range siblings {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Checks that `send_empty_value_when` names a Boolean sibling, on a field
+// whose sibling can be looked up by path when building the request.
+func (t Type) validateSendEmptyValueWhen(rName string) {
+	if t.SendEmptyValueWhen == "" {
+		return
+	}
+
+	if t.SendEmptyValue {
+		validationFailure("Property %s in resource %s cannot set both `send_empty_value` and `send_empty_value_when`.", t.Lineage(), rName)
+	}
+	// the sibling is read by its path, which isn't known for elements of
+	// arrays and maps
+	for p := t.ParentMetadata; p != nil; p = p.ParentMetadata {
+		if p.IsA("Array") || p.IsA("Map") {
+			validationFailure("Property %s in resource %s cannot set `send_empty_value_when` inside an Array or Map.", t.Lineage(), rName)
+		}
+	}
+
+	source := t.SendEmptyValueWhenSource()
+	if source == nil {
+		validationFailure("Property %s in resource %s sets `send_empty_value_when` to `%s`, which is not a sibling field.", t.Lineage(), rName, t.SendEmptyValueWhen)
+		return
+	}
+	if !source.IsA("Boolean") {
+		validationFailure("Property %s in resource %s sets `send_empty_value_when` to `%s`, which is not a Boolean.", t.Lineage(), rName, t.SendEmptyValueWhen)
+	}
+}
+
+// Checks that `default_from_field` names an existing sibling that doesn't
+// itself default from this field, on a field the CustomizeDiff can set.
+func (t Type) validateDefaultFromField(rName string) {
+	if t.DefaultFromField == "" {
+		return
+	}
+
+	if t.Required || t.Output {
+		validationFailure("Property %s in resource %s cannot set `default_from_field` with `required` or `output`.", t.Lineage(), rName)
+	}
+	if t.DefaultValue != nil || t.DefaultFromApi {
+		validationFailure("Property %s in resource %s cannot set `default_from_field` with `default_value` or `default_from_api`.", t.Lineage(), rName)
+	}
+	// a CustomizeDiff can only set new values for top-level fields
+	if strings.Contains(t.TerraformLineage(), ".") {
+		validationFailure("Property %s in resource %s can only set `default_from_field` on a top-level field.", t.Lineage(), rName)
+	}
+
+	source := t.DefaultFromFieldSource()
+	if source == nil {
+		validationFailure("Property %s in resource %s defaults from `%s`, which is not a sibling field.", t.Lineage(), rName, t.DefaultFromField)
+		return
+	}
+	if source.Name == t.Name || source.DefaultFromField == t.Name {
+		validationFailure("Property %s in resource %s defaults from `%s`, which defaults from it in turn.", t.Lineage(), rName, t.DefaultFromField)
+	}
+}
+
+// Provider-level attributes that `default_from_provider` can name.
+var providerDefaultAttributes = []string{"project", "region", "zone"}
+
+// Checks that `default_from_provider` names a known provider attribute, on a
+// String field that is otherwise left empty.
+func (t Type) validateDefaultFromProvider(rName string) {
+	if t.DefaultFromProvider == "" {
+		return
+	}
+
+	if !slices.Contains(providerDefaultAttributes, t.DefaultFromProvider) {
+		validationFailure("Property %s in resource %s defaults from provider attribute `%s`, which is not one of %v.", t.Lineage(), rName, t.DefaultFromProvider, providerDefaultAttributes)
+	}
+	if !t.IsA("String") {
+		validationFailure("Property %s in resource %s can only set `default_from_provider` if it is a String.", t.Lineage(), rName)
+	}
+	if t.Required || t.Output {
+		validationFailure("Property %s in resource %s cannot set `default_from_provider` with `required` or `output`.", t.Lineage(), rName)
+	}
+	if t.DefaultValue != nil || t.DefaultFromApi || t.DefaultFromField != "" {
+		validationFailure("Property %s in resource %s cannot set `default_from_provider` with `default_value`, `default_from_api` or `default_from_field`.", t.Lineage(), rName)
+	}
+	if t.CustomExpand != "" {
+		validationFailure("Property %s in resource %s cannot set both `default_from_provider` and `custom_expand`.", t.Lineage(), rName)
+	}
+}
+
+// Returns whether the property is nested within an Array.
+func (t Type) inArray() bool {
+	for p := t.ParentMetadata; p != nil; p = p.ParentMetadata {
+		if p.IsA("Array") {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Type) Parent() *Type {
+	return t.ParentMetadata
+}
 
 func (t Type) MinVersionObj() *product.Version {
 	if t.MinVersion != "" {
@@ -578,18 +1523,34 @@ func (t Type) MinVersionObj() *product.Version {
 	}
 }
 
-func (t *Type) exactVersionObj() *product.Version {
-	if t.ExactVersion == "" {
+// Returns the versions set by `exact_version` or `exact_versions`, the only
+// versions the field is included in, or nil if neither is set.
+func (t *Type) exactVersionObjs() []*product.Version {
+	var versions []*product.Version
+	if t.ExactVersion != "" {
+		versions = append(versions, t.ResourceMetadata.ProductMetadata.versionObj(t.ExactVersion))
+	}
+	for _, v := range t.ExactVersions {
+		versions = append(versions, t.ResourceMetadata.ProductMetadata.versionObj(v))
+	}
+
+	return versions
+}
+
+func (t *Type) requiredVersionObj() *product.Version {
+	if t.RequiredVersion == "" {
 		return nil
 	}
 
-	return t.ResourceMetadata.ProductMetadata.versionObj(t.ExactVersion)
+	return t.ResourceMetadata.ProductMetadata.versionObj(t.RequiredVersion)
 }
 
 func (t *Type) ExcludeIfNotInVersion(version *product.Version) {
 	if !t.Exclude {
-		if versionObj := t.exactVersionObj(); versionObj != nil {
-			t.Exclude = versionObj.CompareTo(version) != 0
+		if versionObjs := t.exactVersionObjs(); len(versionObjs) > 0 {
+			t.Exclude = !slices.ContainsFunc(versionObjs, func(v *product.Version) bool {
+				return v.CompareTo(version) == 0
+			})
 		}
 
 		if !t.Exclude {
@@ -597,6 +1558,10 @@ func (t *Type) ExcludeIfNotInVersion(version *product.Version) {
 		}
 	}
 
+	if versionObj := t.requiredVersionObj(); versionObj != nil {
+		t.Required = version.CompareTo(versionObj) >= 0
+	}
+
 	if t.IsA("NestedObject") {
 		for _, p := range t.Properties {
 			p.ExcludeIfNotInVersion(version)
@@ -606,6 +1571,72 @@ func (t *Type) ExcludeIfNotInVersion(version *product.Version) {
 	}
 }
 
+// Returns a copy of the property and its whole subtree, so that the copy can
+// be modified (eg. by ExcludeIfNotInVersion or SetDefault) without affecting
+// the original. The ParentMetadata of nested properties is re-linked to
+// their copied parent, and every copied property belongs to resource r.
+func (t *Type) DeepCopy(r *Resource) *Type {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	c.ResourceMetadata = r
+	c.Conflicts = slices.Clone(t.Conflicts)
+	c.AtLeastOneOf = slices.Clone(t.AtLeastOneOf)
+	c.ExactlyOneOf = slices.Clone(t.ExactlyOneOf)
+	c.RequiredWith = slices.Clone(t.RequiredWith)
+	c.ExactlyOneOfGroups = cloneGroups(t.ExactlyOneOfGroups)
+	c.AtLeastOneOfGroups = cloneGroups(t.AtLeastOneOfGroups)
+	c.RequiredWithGroups = cloneGroups(t.RequiredWithGroups)
+	c.EnumValues = slices.Clone(t.EnumValues)
+	c.DeprecatedEnumValues = maps.Clone(t.DeprecatedEnumValues)
+	c.UpdateMaskFields = slices.Clone(t.UpdateMaskFields)
+	c.SchemaVersionChanges = slices.Clone(t.SchemaVersionChanges)
+	c.ExactVersions = slices.Clone(t.ExactVersions)
+	c.Aliases = slices.Clone(t.Aliases)
+
+	switch v := t.DefaultValue.(type) {
+	case []string:
+		c.DefaultValue = slices.Clone(v)
+	case []interface{}:
+		c.DefaultValue = slices.Clone(v)
+	}
+
+	if t.Properties != nil {
+		c.Properties = make([]*Type, len(t.Properties))
+		for i, p := range t.Properties {
+			c.Properties[i] = c.copyChild(t, p, r)
+		}
+	}
+	c.ItemType = c.copyChild(t, t.ItemType, r)
+	c.ValueType = c.copyChild(t, t.ValueType, r)
+
+	return &c
+}
+
+func cloneGroups(groups [][]string) [][]string {
+	if groups == nil {
+		return nil
+	}
+
+	c := make([][]string, len(groups))
+	for i, group := range groups {
+		c[i] = slices.Clone(group)
+	}
+	return c
+}
+
+// Copies a child of the original property, re-linking it to this copy if it
+// was linked to the original.
+func (t *Type) copyChild(original, child *Type, r *Resource) *Type {
+	c := child.DeepCopy(r)
+	if c != nil && c.ParentMetadata == original {
+		c.ParentMetadata = t
+	}
+	return c
+}
+
 func (t Type) IsA(clazz string) bool {
 	if clazz == "" {
 		log.Fatalf("class cannot be empty")
@@ -618,6 +1649,36 @@ func (t Type) IsA(clazz string) bool {
 	return t.Type == clazz
 }
 
+// Returns the maximum nesting depth of the property, counting the property
+// itself, through NestedObject, Array and Map branches. The item type of an
+// Array and the value type of a Map don't add a level of their own.
+func (t Type) Depth() int {
+	depth, _ := t.deepestProperty()
+	return depth
+}
+
+// Returns the maximum nesting depth of the property and the property found at
+// that depth.
+func (t *Type) deepestProperty() (int, *Type) {
+	var children []*Type
+	switch {
+	case t.IsA("NestedObject"):
+		children = t.Properties
+	case t.IsA("Array") && t.ItemType != nil && t.ItemType.IsA("NestedObject"):
+		children = t.ItemType.Properties
+	case t.IsA("Map") && t.ValueType != nil:
+		children = t.ValueType.Properties
+	}
+
+	depth, deepest := 0, t
+	for _, p := range children {
+		if d, dp := p.deepestProperty(); d > depth {
+			depth, deepest = d, dp
+		}
+	}
+	return depth + 1, deepest
+}
+
 // Returns nested properties for this property.
 func (t Type) NestedProperties() []*Type {
 	props := make([]*Type, 0)
@@ -637,30 +1698,554 @@ func (t Type) NestedProperties() []*Type {
 		})
 	default:
 	}
-	return props
-}
+	return props
+}
+
+// Returns the keys the property adds to a create or update request body, by
+// API name: its own key and, for nested objects and arrays of them, a dotted
+// key for each nested field that is sent, eg. "policy" and
+// "policy.allowedRegions". Properties that are never sent have no keys.
+func (t Type) RequestBodyKeys() []string {
+	if (t.Output && !t.IsA("Fingerprint") && !t.IsA("KeyValueEffectiveLabels")) || t.UrlParamOnly || t.ClientSide || t.IgnoreWrite {
+		return nil
+	}
+
+	keys := []string{t.ApiName}
+	// map values are keyed by the user's keys rather than a field name
+	if t.IsA("Map") {
+		return keys
+	}
+	for _, p := range t.NestedProperties() {
+		for _, k := range p.RequestBodyKeys() {
+			keys = append(keys, fmt.Sprintf("%s.%s", t.ApiName, k))
+		}
+	}
+	return keys
+}
+
+func (t Type) Removed() bool {
+	return t.RemovedMessage != ""
+}
+
+func (t Type) Deprecated() bool {
+	return t.DeprecationMessage != ""
+}
+
+// Returns whether the property is an Array, Map or NestedObject populated by
+// the API. It's Optional+Computed in the schema like other default_from_api
+// fields, and the expanders leave it out of the request when it's effectively
+// empty, rather than sending a block of empty values.
+//
+// The check in the generated code doesn't know about allow_empty_object, so
+// it's only used when a value with every nested block set but empty is
+// effectively empty for this property too.
+func (t Type) OmitsEffectivelyEmpty() bool {
+	if !t.DefaultFromApi || t.AllowEmptyObject || !(t.IsA("Array") || t.IsA("Map") || t.IsA("NestedObject")) {
+		return false
+	}
+	return t.IsEffectivelyEmpty(t.emptyBlocksValue())
+}
+
+// Returns a value for the property with every nested block set, but empty.
+func (t Type) emptyBlocksValue() interface{} {
+	switch {
+	case t.IsA("NestedObject"):
+		value := make(map[string]interface{})
+		for _, p := range t.Properties {
+			value[p.ApiName] = p.emptyBlocksValue()
+		}
+		return value
+	case t.IsA("Array") && t.ItemType != nil:
+		return []interface{}{t.ItemType.emptyBlocksValue()}
+	case t.IsA("Map") && t.ValueType != nil:
+		return map[string]interface{}{"key": t.ValueType.emptyBlocksValue()}
+	}
+	return nil
+}
+
+// Returns whether a value for this property is empty in the sense used by
+// allow_empty_object: nil, a zero primitive, or a list/object whose entries
+// are all effectively empty themselves. A nested object that sets
+// allow_empty_object is meaningful even when it has no values set, so it is
+// never treated as empty once present.
+func (t Type) IsEffectivelyEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		// Map keys are set by the user, so each entry is checked against the
+		// value type
+		if t.IsA("Map") && t.ValueType != nil {
+			for _, val := range v {
+				if t.ValueType.AllowEmptyObject && val != nil {
+					return false
+				}
+				if !t.ValueType.IsEffectivelyEmpty(val) {
+					return false
+				}
+			}
+			return true
+		}
+		for key, val := range v {
+			child := t.nestedPropertyByKey(key)
+			if child == nil {
+				child = &Type{}
+			}
+			if child.AllowEmptyObject && child.IsA("NestedObject") && val != nil {
+				return false
+			}
+			if !child.IsEffectivelyEmpty(val) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		item := t
+		if t.IsA("Array") && t.ItemType != nil {
+			item = *t.ItemType
+		}
+		for _, val := range v {
+			if !item.IsEffectivelyEmpty(val) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.ValueOf(v).IsZero()
+	}
+}
+
+// Returns the nested property matching a key of an object value, which may
+// be either the Terraform field name or the API field name.
+func (t Type) nestedPropertyByKey(key string) *Type {
+	var props []*Type
+	switch {
+	case t.IsA("NestedObject"):
+		props = t.Properties
+	case t.IsA("Array") && t.ItemType != nil && t.ItemType.IsA("NestedObject"):
+		props = t.ItemType.Properties
+	}
+
+	for _, p := range props {
+		if google.Underscore(p.Name) == key || p.ApiName == key || p.Name == key {
+			return p
+		}
+	}
+	return nil
+}
+
+func (t *Type) GetDescription() string {
+	return strings.TrimSpace(strings.TrimRight(t.Description, "\n"))
+}
+
+// Returns the description truncated to at most maxLen characters for use in
+// the schema, cut on a word boundary and ending with "..." when shortened.
+// The full description is still used in the docs. A maxLen of 0 or less
+// leaves the description as is.
+func (t Type) SchemaDescription(maxLen int) string {
+	desc := []rune(t.GetDescription())
+	if maxLen <= 0 || len(desc) <= maxLen {
+		return string(desc)
+	}
+
+	const ellipsis = "..."
+	limit := maxLen - len(ellipsis)
+	if limit <= 0 {
+		return string(desc[:maxLen])
+	}
+
+	// keep the last whole word, unless a single word is longer than the limit
+	cut := desc[:limit]
+	if !unicode.IsSpace(desc[limit]) {
+		if i := lastSpace(cut); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(string(cut), unicode.IsSpace) + ellipsis
+}
+
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the description used in the docs. For configurable enums, and
+// arrays of enums, the default value and the possible values are appended
+// unless `exclude_docs_values` is set. With `auto_enum_docs`, the possible
+// values are left out if the description already lists them.
+func (t *Type) DocDescription() string {
+	desc := t.GetDescription()
+	if t.Output {
+		return desc
+	}
+
+	enum := t
+	if t.IsA("Array") && t.ItemType != nil && t.ItemType.IsA("Enum") {
+		enum = t.ItemType
+	}
+	if !enum.IsA("Enum") || enum.ExcludeDocsValues {
+		return desc
+	}
+
+	lines := []string{}
+	if desc != "" {
+		lines = append(lines, desc)
+	}
+	if enum.DefaultValue != nil {
+		lines = append(lines, fmt.Sprintf("Default value is `%v`.", enum.DefaultValue))
+	}
+	// values from enum_values_from are only known to the generated code
+	if enum.EnumValuesFrom == "" && !(enum.AutoEnumDocs && describesValues(desc, enum.EnumValues)) {
+		if enum == t {
+			lines = append(lines, fmt.Sprintf("Possible values are: %s.", enum.EnumValuesToString("`", false)))
+		} else {
+			lines = append(lines, fmt.Sprintf("Each value may be one of: %s.", enum.EnumValuesToString("`", false)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns whether every one of values is mentioned in desc as a whole word.
+func describesValues(desc string, values []string) bool {
+	for _, v := range values {
+		if !regexp.MustCompile(`\b` + regexp.QuoteMeta(v) + `\b`).MatchString(desc) {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// The types an Array can hold. Output-only arrays may also hold arrays, as
+// their values are read from the API as-is.
+var allowedItemTypes = []string{"String", "Integer", "Double", "Boolean", "Time", "Enum", "ResourceRef", "NestedObject", "KeyValuePairs"}
+
+// The types the inner Array of an Array of Arrays can hold.
+var nestedArrayItemTypes = []string{"String", "Integer", "Double", "Boolean", "Time"}
+
+// Checks that an Array has an item_type, and that it is one of the types an
+// Array can hold. Arrays of Arrays are capped at two levels of nesting.
+func (t Type) validateItemType(rName string) {
+	if t.ItemType == nil {
+		validationFailure("Property %s in resource %s is an Array without an `item_type`.", t.Lineage(), rName)
+		return
+	}
+
+	if t.ItemType.IsA("Array") {
+		if t.ItemType.ItemType != nil && t.ItemType.ItemType.IsA("Array") {
+			validationFailure("Property %s in resource %s nests Arrays more than two levels deep.", t.Lineage(), rName)
+		}
+		if t.Output || t.IsNestedArray() {
+			return
+		}
+		validationFailure("Property %s in resource %s is an Array of Arrays, but the inner `item_type` should be one of %v.", t.Lineage(), rName, nestedArrayItemTypes)
+	}
+
+	if slices.Contains(allowedItemTypes, t.ItemType.Type) {
+		return
+	}
+
+	validationFailure("Property %s in resource %s is an Array of %s, but `item_type` should be one of %v.", t.Lineage(), rName, t.ItemType.Type, allowedItemTypes)
+}
+
+// Returns whether the property holds a free-form string value.
+func (t Type) isStringBacked() bool {
+	return t.IsA("String") || t.IsA("Time")
+}
+
+// The primitive types, which a Map can hold besides NestedObject.
+var primitiveTypes = []string{"String", "Integer", "Double", "Boolean", "Time", "Enum"}
+
+// Checks that a Map has a valid value_type and key_name, and that the fields
+// describing the key of a Map are only set on Map properties, as they are
+// ignored on other types.
+func (t Type) validateMapFields(rName string) {
+	if t.IsA("Map") {
+		if t.ValueType == nil {
+			validationFailure("Property %s in resource %s is a Map without a `value_type`.", t.Lineage(), rName)
+		} else if !t.ValueType.IsA("NestedObject") && !slices.Contains(primitiveTypes, t.ValueType.Type) {
+			validationFailure("Property %s in resource %s is a Map of %s, but `value_type` should be a NestedObject or one of %v.", t.Lineage(), rName, t.ValueType.Type, primitiveTypes)
+		}
+		if !terraformFieldNameRegex.MatchString(t.KeyName) {
+			validationFailure("Property %s in resource %s has `key_name` %q, which should be a snake_case Terraform field name.", t.Lineage(), rName, t.KeyName)
+		}
+		return
+	}
+
+	fields := map[string]string{
+		"key_expander":           t.KeyExpander,
+		"key_diff_suppress_func": t.KeyDiffSuppressFunc,
+		"key_name":               t.KeyName,
+		"key_description":        t.KeyDescription,
+	}
+	for _, field := range []string{"key_expander", "key_diff_suppress_func", "key_name", "key_description"} {
+		if fields[field] != "" {
+			validationFailure("Property %s in resource %s can only set `%s` if it is a Map.", t.Lineage(), rName, field)
+		}
+	}
+}
+
+// Returns the ValidateFunc built from the declarative validation fields of the
+// property, such as `min_length`, `max_length` and `pattern`, or an empty
+// string if none are set. An explicit `validation` takes precedence in the
+// schema.
+func (t Type) DeclaredValidation() string {
+	var funcs []string
+
+	if t.MinLength > 0 || t.MaxLength > 0 {
+		max := "math.MaxInt"
+		if t.MaxLength > 0 {
+			max = fmt.Sprintf("%d", t.MaxLength)
+		}
+		funcs = append(funcs, fmt.Sprintf("validation.StringLenBetween(%d, %s)", t.MinLength, max))
+	}
+
+	if t.Pattern != "" {
+		message := t.PatternMessage
+		if message == "" {
+			message = fmt.Sprintf("must match the pattern %s", t.Pattern)
+		}
+		funcs = append(funcs, fmt.Sprintf("validation.StringMatch(regexp.MustCompile(`%s`), %q)", t.Pattern, message))
+	}
+
+	switch len(funcs) {
+	case 0:
+		return ""
+	case 1:
+		return funcs[0]
+	default:
+		return fmt.Sprintf("validation.All(%s)", strings.Join(funcs, ", "))
+	}
+}
+
+// Returns the item ValidateFunc built from `item_minimum` and
+// `item_maximum`, or an empty string if neither is set.
+func (t Type) ItemRangeValidation() string {
+	if t.ItemMinimum == "" && t.ItemMaximum == "" {
+		return ""
+	}
+
+	kind := "Int"
+	if t.ItemType != nil && t.ItemType.IsA("Double") {
+		kind = "Float"
+	}
+
+	switch {
+	case t.ItemMaximum == "":
+		return fmt.Sprintf("validation.%sAtLeast(%s)", kind, t.ItemMinimum)
+	case t.ItemMinimum == "":
+		return fmt.Sprintf("validation.%sAtMost(%s)", kind, t.ItemMaximum)
+	default:
+		return fmt.Sprintf("validation.%sBetween(%s, %s)", kind, t.ItemMinimum, t.ItemMaximum)
+	}
+}
+
+// Returns the Go type of the expanded value of the property.
+func (t Type) GoType() string {
+	switch {
+	case t.IsA("Boolean"):
+		return "bool"
+	case t.IsA("Integer"):
+		return "int"
+	case t.IsA("Double"):
+		return "float64"
+	case t.IsA("Array"):
+		return "[]interface{}"
+	case t.IsA("NestedObject"), t.IsA("Map"):
+		return "map[string]interface{}"
+	case strings.HasPrefix(t.Type, "KeyValue"):
+		return "map[string]string"
+	default:
+		return "string"
+	}
+}
+
+// Checks that `set_hash_expr` is only set on sets of objects, instead of
+// `set_hash_func`, and only references fields of the set's items.
+func (t Type) validateSetHashExpr(rName string) {
+	if t.SetHashExpr == "" {
+		return
+	}
+
+	if t.SetHashFunc != "" {
+		validationFailure("Property %s in resource %s cannot set both `set_hash_func` and `set_hash_expr`.", t.Lineage(), rName)
+	}
+
+	if !t.IsObjectSet() {
+		validationFailure("Property %s in resource %s can only set `set_hash_expr` if it is an Array of NestedObject with `is_set`.", t.Lineage(), rName)
+		return
+	}
+
+	_, fields := t.setHashExprParts()
+	if len(fields) == 0 || strings.Contains(setHashExprFieldRegex.ReplaceAllString(t.SetHashExpr, ""), "{{") {
+		validationFailure("`set_hash_expr` %s of property %s in resource %s should reference fields of its items as {{.field_name}}.", t.SetHashExpr, t.Lineage(), rName)
+	}
+
+	for _, field := range fields {
+		exists := slices.ContainsFunc(t.ItemType.Properties, func(p *Type) bool {
+			return google.Underscore(p.Name) == field
+		})
+		if !exists {
+			validationFailure("`set_hash_expr` of property %s in resource %s references %s, which isn't a field of its items.", t.Lineage(), rName, field)
+		}
+	}
+}
+
+// Splits `set_hash_expr` into a fmt format string and the fields it
+// references, in order.
+func (t Type) setHashExprParts() (string, []string) {
+	var fields []string
+	for _, match := range setHashExprFieldRegex.FindAllStringSubmatch(t.SetHashExpr, -1) {
+		fields = append(fields, match[1])
+	}
+
+	format := strings.ReplaceAll(t.SetHashExpr, "%", "%%")
+	format = setHashExprFieldRegex.ReplaceAllString(format, "%v")
+	return format, fields
+}
+
+// Returns the function used to hash the items of a set: `set_hash_func` if
+// set, or the function generated from `set_hash_expr`.
+func (t Type) SetHashFunction() string {
+	if t.SetHashExpr != "" {
+		return fmt.Sprintf("%sSetHash", t.NamespaceProperty())
+	}
+
+	return t.SetHashFunc
+}
+
+// Returns the Go expression building the string hashed for an item `raw` of
+// a set from `set_hash_expr`.
+func (t Type) SetHashExprGo() string {
+	format, fields := t.setHashExprParts()
+
+	args := []string{fmt.Sprintf("%q", format)}
+	for _, field := range fields {
+		args = append(args, fmt.Sprintf("raw[%q]", field))
+	}
+	return fmt.Sprintf("fmt.Sprintf(%s)", strings.Join(args, ", "))
+}
+
+// Returns the zero value of the Go type returned by GoType, for use in
+// templates.
+func (t Type) GoZeroValue() string {
+	switch t.GoType() {
+	case "bool":
+		return "false"
+	case "int":
+		return "0"
+	case "float64":
+		return "0.0"
+	case "string":
+		return `""`
+	default:
+		return "nil"
+	}
+}
+
+// Returns a plausible value for the property, keyed by Terraform field names
+// for objects, for use in generated test configs. The default value is used
+// when present. Output and excluded fields, and fields with no literal value
+// to offer, return nil.
+func (t Type) SampleValue() interface{} {
+	if t.Output || t.Exclude {
+		return nil
+	}
+
+	if t.DefaultValue != nil {
+		return t.DefaultValue
+	}
+
+	switch {
+	case t.IsA("Boolean"):
+		return true
+	case t.IsA("Integer"):
+		return 1
+	case t.IsA("Double"):
+		return 1.0
+	case t.IsA("Time"):
+		return "2024-01-01T00:00:00Z"
+	case t.IsA("Quantity"):
+		return "1Gi"
+	case t.IsA("Enum"):
+		if len(t.EnumValues) == 0 {
+			return nil
+		}
+		return t.EnumValues[0]
+	case t.IsA("ResourceRef"):
+		return fmt.Sprintf("sample-%s", google.Underscore(t.Resource))
+	case strings.HasPrefix(t.Type, "KeyValue"):
+		return map[string]string{"key": "value"}
+	case t.IsA("Array"):
+		item := t.ItemType.SampleValue()
+		if item == nil {
+			return nil
+		}
+		return []interface{}{item}
+	case t.IsA("NestedObject"):
+		sample := make(map[string]interface{})
+		for _, p := range t.UserProperties() {
+			if v := p.SampleValue(); v != nil {
+				sample[google.Underscore(p.Name)] = v
+			}
+		}
+		return sample
+	case t.IsA("Map"):
+		entry, ok := t.ValueType.SampleValue().(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		entry[t.KeyName] = "sample-key"
+		return []interface{}{entry}
+	case t.IsA("String"):
+		return fmt.Sprintf("sample-%s", google.Underscore(t.Name))
+	default:
+		return nil
+	}
+}
+
+// Returns the example value of the property formatted for documentation, with
+// strings quoted and arrays rendered as lists, or an empty string if no
+// example is set.
+func (t Type) DocExample() string {
+	if t.Example == "" {
+		return ""
+	}
+
+	switch t.TFType(t.Type) {
+	case "schema.TypeString":
+		return fmt.Sprintf("%q", t.Example)
+	case "schema.TypeList", "schema.TypeSet":
+		if !t.IsA("Array") || strings.HasPrefix(t.Example, "[") {
+			return t.Example
+		}
+		var items []string
+		for _, item := range strings.Split(t.Example, ",") {
+			item = strings.TrimSpace(item)
+			if t.ItemType.TFType(t.ItemType.Type) == "schema.TypeString" {
+				item = fmt.Sprintf("%q", item)
+			}
+			items = append(items, item)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	}
 
-func (t Type) Removed() bool {
-	return t.RemovedMessage != ""
+	return t.Example
 }
 
-func (t Type) Deprecated() bool {
-	return t.DeprecationMessage != ""
-}
+// Returns whether the property is an Array of Arrays of primitives, eg. a
+// matrix of values, which is a list of lists in the schema.
+func (t Type) IsNestedArray() bool {
+	if !t.IsA("Array") || t.ItemType == nil || !t.ItemType.IsA("Array") || t.ItemType.ItemType == nil {
+		return false
+	}
 
-func (t *Type) GetDescription() string {
-	return strings.TrimSpace(strings.TrimRight(t.Description, "\n"))
+	return slices.Contains(nestedArrayItemTypes, t.ItemType.ItemType.Type)
 }
 
-// TODO rewrite: validation
-// class Array < Composite
-//     check :item_type, type: [::String, NestedObject, ResourceRef, Enum], required: true
-
-//     unless @item_type.is_a?(NestedObject) || @item_type.is_a?(ResourceRef) \
-//         || @item_type.is_a?(Enum) || type?(@item_type)
-//       raise "Invalid type //{@item_type}"
-//     end
-
 // This function is for array field
 func (t Type) ItemTypeClass() string {
 	if !t.IsA("Array") {
@@ -682,6 +2267,8 @@ func (t Type) TFType(s string) string {
 		return "schema.TypeString"
 	case "Time":
 		return "schema.TypeString"
+	case "Quantity":
+		return "schema.TypeString"
 	case "Enum":
 		return "schema.TypeString"
 	case "ResourceRef":
@@ -709,6 +2296,24 @@ func (t Type) TFType(s string) string {
 	return "schema.TypeString"
 }
 
+// Returns the schema type of the property itself, which is schema.TypeSet
+// for `is_set` and `unordered_list` properties, including sets of objects,
+// and the TFType of its type otherwise.
+func (t Type) SchemaType() string {
+	if t.IsSet || t.UnorderedList {
+		return "schema.TypeSet"
+	}
+
+	return t.TFType(t.Type)
+}
+
+// Returns whether the property is an `is_set` Array of NestedObject, whose
+// items are generated as a separate schema function and hashed with
+// SetHashFunction.
+func (t Type) IsObjectSet() bool {
+	return (t.IsSet || t.UnorderedList) && t.IsA("Array") && t.ItemType != nil && t.ItemType.IsA("NestedObject")
+}
+
 // TODO rewrite: validation
 // // Represents an enum, and store is valid values
 // class Enum < Primitive
@@ -756,21 +2361,75 @@ func (t Type) ResourceRef() *Resource {
 	resources := google.Select(product.Objects, func(obj *Resource) bool {
 		return obj.Name == t.Resource
 	})
+	if len(resources) == 0 {
+		return nil
+	}
 
 	return resources[0]
 }
 
-// TODO rewrite: validation
-//   func (t *Type) check_resource_ref_property_exists
-//     return unless defined?(resource_ref.all_user_properties)
-
-//     exported_props = resource_ref.all_user_properties
-//     exported_props << Api::Type::String.new('selfLink') \
-//       if resource_ref.has_self_link
-//     raise "'//{@imports}' does not exist on '//{@resource}'" \
-//       if exported_props.none? { |p| p.name == @imports }
-//   end
-// end
+// Returns the names of the resources referenced by ResourceRef fields in the
+// property's subtree, including inside arrays and maps, in the order they're
+// first found. Excluded fields are skipped.
+func (t Type) ResourceRefTargets() []string {
+	var targets []string
+	if t.Exclude {
+		return targets
+	}
+
+	if t.IsA("ResourceRef") && t.Resource != "" {
+		targets = append(targets, t.Resource)
+	}
+
+	children := slices.Clone(t.Properties)
+	if t.ItemType != nil {
+		children = append(children, t.ItemType)
+	}
+	if t.ValueType != nil {
+		children = append(children, t.ValueType)
+	}
+	for _, c := range children {
+		targets = appendMissing(targets, c.ResourceRefTargets())
+	}
+	return targets
+}
+
+// Checks that the `imports` of each ResourceRef in the property's subtree
+// names a property of the referenced resource, `selfLink` if the resource
+// has a self link, or the Terraform `id` every resource has. References to
+// resources that aren't loaded into the product are skipped, so this is run
+// once the product's resources are known.
+func (t Type) validateResourceRefImports(rName string) {
+	if t.Exclude {
+		return
+	}
+
+	if t.IsA("ResourceRef") && t.Imports != "" {
+		if target := t.ResourceRef(); target != nil && !target.Exclude && !t.importsExistOn(target) {
+			validationFailure("Property %s in resource %s imports '%s', which does not exist on '%s'.", t.Lineage(), rName, t.Imports, t.Resource)
+		}
+	}
+
+	children := slices.Clone(t.Properties)
+	if t.ItemType != nil {
+		children = append(children, t.ItemType)
+	}
+	if t.ValueType != nil {
+		children = append(children, t.ValueType)
+	}
+	for _, c := range children {
+		c.validateResourceRefImports(rName)
+	}
+}
+
+func (t Type) importsExistOn(target *Resource) bool {
+	if t.Imports == "id" || (t.Imports == "selfLink" && target.HasSelfLink) {
+		return true
+	}
+	return slices.ContainsFunc(target.AllUserProperties(), func(p *Type) bool {
+		return p.Name == t.Imports
+	})
+}
 
 // // An structured object composed of other objects.
 // class NestedObject < Composite
@@ -795,26 +2454,91 @@ func (t Type) AllProperties() []*Type {
 	return t.Properties
 }
 
+// Returns the properties generated for the resource
 func (t Type) UserProperties() []*Type {
+	return t.UserPropertiesInScope(SCOPE_RESOURCE)
+}
+
+// Returns the properties generated for the given artifact, either
+// "resource" or "datasource"
+func (t Type) UserPropertiesInScope(scope string) []*Type {
 	if t.IsA("NestedObject") {
 		if t.Properties == nil {
 			log.Fatalf("Field '{%s}' properties are nil!", t.Lineage())
 		}
 
-		return google.Reject(t.Properties, func(p *Type) bool {
-			return p.Exclude
-		})
+		return sortBySourceIndex(google.Select(t.Properties, func(p *Type) bool {
+			return p.InScope(scope)
+		}))
 	}
 	return nil
 }
 
+// Records the position of each property as declared in the YAML, before any
+// override merging or generated fields change the order of the slice.
+func recordSourceIndexes(props []*Type) {
+	for i, p := range props {
+		p.SourceIndex = i + 1
+	}
+}
+
+// Positions the properties that weren't declared in the YAML, such as
+// generated labels fields, after the declared ones in their current order.
+func recordGeneratedIndexes(props []*Type) {
+	last := 0
+	for _, p := range props {
+		last = max(last, p.SourceIndex)
+	}
+	for _, p := range props {
+		if p.SourceIndex == 0 {
+			last++
+			p.SourceIndex = last
+		}
+	}
+}
+
+// Returns props ordered by source position, breaking ties by name.
+// Properties without a recorded position sort last in their current order.
+func sortBySourceIndex(props []*Type) []*Type {
+	slices.SortStableFunc(props, func(a, b *Type) int {
+		switch {
+		case a.SourceIndex == b.SourceIndex && a.SourceIndex == 0:
+			return 0
+		case a.SourceIndex == b.SourceIndex:
+			return CompareByName(a, b)
+		case a.SourceIndex == 0:
+			return 1
+		case b.SourceIndex == 0:
+			return -1
+		}
+		return a.SourceIndex - b.SourceIndex
+	})
+	return props
+}
+
+// Returns whether the property is generated for the given artifact, either
+// "resource" or "datasource". Excluded properties are never in scope.
+func (t Type) InScope(scope string) bool {
+	if t.Exclude {
+		return false
+	}
+
+	return t.Scope == "" || t.Scope == SCOPE_BOTH || t.Scope == scope
+}
+
 // Returns the list of top-level properties once any nested objects with
 // flatten_object set to true have been collapsed
 func (t *Type) RootProperties() []*Type {
+	return t.RootPropertiesInScope(SCOPE_RESOURCE)
+}
+
+// Returns the list of top-level properties generated for the given artifact
+// once any nested objects with flatten_object set to true have been collapsed
+func (t *Type) RootPropertiesInScope(scope string) []*Type {
 	props := make([]*Type, 0)
-	for _, p := range t.UserProperties() {
+	for _, p := range t.UserPropertiesInScope(scope) {
 		if p.FlattenObject {
-			props = google.Concat(props, p.RootProperties())
+			props = google.Concat(props, p.RootPropertiesInScope(scope))
 		} else {
 			props = append(props, p)
 		}
@@ -892,6 +2616,181 @@ func propertyWithIgnoreWrite(ignoreWrite bool) func(*Type) {
 	}
 }
 
+// Immutable fields are never updated, so setting any of the update options
+// on them is contradictory.
+func (t *Type) validateImmutable(rName string) {
+	if !t.Immutable {
+		return
+	}
+
+	if t.UpdateUrl != "" {
+		validationFailure("Property %s cannot be immutable and have an `update_url` at the same time in resource %s.", t.Lineage(), rName)
+	}
+
+	// update_verb is defaulted to the resource's update_verb, so only a
+	// different value can have been set on the property itself.
+	if t.UpdateVerb != "" && t.ResourceMetadata != nil && t.UpdateVerb != t.ResourceMetadata.UpdateVerb {
+		validationFailure("Property %s cannot be immutable and have an `update_verb` at the same time in resource %s.", t.Lineage(), rName)
+	}
+
+	if len(t.UpdateMaskFields) > 0 {
+		validationFailure("Property %s cannot be immutable and have `update_mask_fields` at the same time in resource %s.", t.Lineage(), rName)
+	}
+}
+
+func (t *Type) validateClientSide(rName string) {
+	if !t.ClientSide {
+		return
+	}
+
+	if t.Output || t.UpdateUrl != "" {
+		validationFailure("Property %s in resource %s cannot set `client_side` with `output` or `update_url`.", t.Lineage(), rName)
+	}
+
+	if t.CustomExpand != "" || t.CustomFlatten != "" {
+		validationFailure("Property %s in resource %s cannot set `client_side` with `custom_expand` or `custom_flatten`, as it's never sent or read.", t.Lineage(), rName)
+	}
+
+	// the value is carried over from the configuration by its path, which
+	// isn't known for array elements or map values
+	for p := t.ParentMetadata; p != nil; p = p.ParentMetadata {
+		if p.IsA("Array") || p.IsA("Map") {
+			validationFailure("Property %s in resource %s cannot set `client_side` inside an Array or Map.", t.Lineage(), rName)
+			break
+		}
+	}
+}
+
+func (t *Type) validateCreateOnlyReadable(rName string) {
+	if !t.CreateOnlyReadable {
+		return
+	}
+
+	if t.Output || t.IgnoreRead {
+		validationFailure("Property %s in resource %s cannot set `create_only_readable` with `output` or `ignore_read`.", t.Lineage(), rName)
+	}
+
+	if t.UpdateUrl != "" {
+		validationFailure("Property %s in resource %s cannot set `create_only_readable` and have an `update_url`.", t.Lineage(), rName)
+	}
+
+	// nested fields are sent with their parent in the update request
+	if t.ParentMetadata != nil {
+		validationFailure("Property %s in resource %s can only set `create_only_readable` on a top-level field.", t.Lineage(), rName)
+	}
+}
+
+func (t *Type) validateDepth(rName string) {
+	depth, deepest := t.deepestProperty()
+	if depth <= MaxPropertyDepth {
+		return
+	}
+
+	if FailOnMaxPropertyDepth {
+		validationFailure("Property %s in resource %s is nested %d levels deep, more than the maximum of %d.", deepest.Lineage(), rName, depth, MaxPropertyDepth)
+	}
+	log.Printf("WARNING: Property %s in resource %s is nested %d levels deep, more than the maximum of %d.", deepest.Lineage(), rName, depth, MaxPropertyDepth)
+}
+
+func (t *Type) validateGroups(rName string) {
+	groups := google.Concat(google.Concat(t.ExactlyOneOfGroups, t.AtLeastOneOfGroups), t.RequiredWithGroups)
+	if len(groups) > 0 && !t.IsA("NestedObject") {
+		validationFailure("Property %s in resource %s can only declare groups if it is a NestedObject.", t.Lineage(), rName)
+	}
+
+	for _, group := range groups {
+		for _, member := range group {
+			if t.groupMember(member) == nil {
+				validationFailure("Group member %s of property %s in resource %s does not exist.", member, t.Lineage(), rName)
+			}
+		}
+	}
+}
+
+func (t *Type) validateLength(rName string) {
+	if t.MinLength == 0 && t.MaxLength == 0 {
+		return
+	}
+
+	if !t.isStringBacked() {
+		validationFailure("Property %s in resource %s can only set `min_length` and `max_length` if it is a string.", t.Lineage(), rName)
+	}
+
+	if t.MinLength < 0 || t.MaxLength < 0 {
+		validationFailure("Property %s in resource %s cannot have a negative `min_length` or `max_length`.", t.Lineage(), rName)
+	}
+
+	if t.MaxLength > 0 && t.MinLength > t.MaxLength {
+		validationFailure("Property %s in resource %s has a `min_length` greater than its `max_length`.", t.Lineage(), rName)
+	}
+}
+
+func (t *Type) validateItemRange(rName string) {
+	if t.ItemMinimum == "" && t.ItemMaximum == "" {
+		return
+	}
+
+	if !t.IsA("Array") || t.ItemType == nil || !(t.ItemType.IsA("Integer") || t.ItemType.IsA("Double")) {
+		validationFailure("Property %s in resource %s can only set `item_minimum` and `item_maximum` if it is an Array of Integer or Double.", t.Lineage(), rName)
+		return
+	}
+
+	if t.ItemValidation.Regex != "" || t.ItemValidation.Function != "" {
+		validationFailure("Property %s in resource %s cannot set `item_minimum` or `item_maximum` with `item_validation`.", t.Lineage(), rName)
+	}
+
+	parse := func(v string) (float64, error) {
+		if t.ItemType.IsA("Integer") {
+			i, err := strconv.ParseInt(v, 10, 64)
+			return float64(i), err
+		}
+		return strconv.ParseFloat(v, 64)
+	}
+
+	lower, minErr := parse(t.ItemMinimum)
+	if t.ItemMinimum != "" && minErr != nil {
+		validationFailure("Property %s in resource %s has an invalid `item_minimum` %s for its %s items.", t.Lineage(), rName, t.ItemMinimum, t.ItemType.Type)
+	}
+
+	upper, maxErr := parse(t.ItemMaximum)
+	if t.ItemMaximum != "" && maxErr != nil {
+		validationFailure("Property %s in resource %s has an invalid `item_maximum` %s for its %s items.", t.Lineage(), rName, t.ItemMaximum, t.ItemType.Type)
+	}
+
+	if minErr == nil && maxErr == nil && lower > upper {
+		validationFailure("Property %s in resource %s has an `item_minimum` greater than its `item_maximum`.", t.Lineage(), rName)
+	}
+}
+
+func (t *Type) validatePattern(rName string) {
+	if t.Pattern == "" {
+		if t.PatternMessage != "" {
+			validationFailure("Property %s in resource %s cannot set `pattern_message` without `pattern`.", t.Lineage(), rName)
+		}
+		return
+	}
+
+	if !t.isStringBacked() {
+		validationFailure("Property %s in resource %s can only set `pattern` if it is a string.", t.Lineage(), rName)
+	}
+
+	if _, err := regexp.Compile(t.Pattern); err != nil {
+		validationFailure("Property %s in resource %s has an invalid `pattern`: %v", t.Lineage(), rName, err)
+	}
+
+	// the pattern is generated as a raw string literal
+	if strings.Contains(t.Pattern, "`") {
+		validationFailure("Property %s in resource %s cannot use a backtick in its `pattern`.", t.Lineage(), rName)
+	}
+}
+
+// Returns whether the property is a "labels" field nested in an object
+// outside of any Array, which gets terraform_labels and effective_labels
+// siblings like the top-level labels field.
+func (t Type) isNestedLabels() bool {
+	return t.Name == "labels" && t.ParentMetadata != nil && !t.inArray()
+}
+
 func (t *Type) validateLabelsField() {
 	productName := t.ResourceMetadata.ProductMetadata.Name
 	resourceName := t.ResourceMetadata.Name
@@ -915,20 +2814,20 @@ func (t *Type) validateLabelsField() {
 
 			// The "labels" field has type Array, so skip this resource
 			!(productName == "Monitoring" && resourceName == "MetricDescriptor") {
-			log.Fatalf("Please use type KeyValueLabels for field %s in resource %s/%s", lineage, productName, resourceName)
+			validationFailure("Please use type KeyValueLabels for field %s in resource %s/%s", lineage, productName, resourceName)
 		}
-	} else if t.IsA("KeyValueLabels") {
-		log.Fatalf("Please don't use type KeyValueLabels for field %s in resource %s/%s", lineage, productName, resourceName)
+	} else if t.IsA("KeyValueLabels") && !t.isNestedLabels() {
+		validationFailure("Please don't use type KeyValueLabels for field %s in resource %s/%s", lineage, productName, resourceName)
 	}
 
 	if lineage == "annotations" || lineage == "metadata.annotations" {
 		if !t.IsA("KeyValueAnnotations") &&
 			// The "annotations" field has "ouput: true", so skip this eap resource
 			!(productName == "Gkeonprem" && resourceName == "BareMetalAdminClusterEnrollment") {
-			log.Fatalf("Please use type KeyValueAnnotations for field %s in resource %s/%s", lineage, productName, resourceName)
+			validationFailure("Please use type KeyValueAnnotations for field %s in resource %s/%s", lineage, productName, resourceName)
 		}
 	} else if t.IsA("KeyValueAnnotations") {
-		log.Fatalf("Please don't use type KeyValueAnnotations for field %s in resource %s/%s", lineage, productName, resourceName)
+		validationFailure("Please don't use type KeyValueAnnotations for field %s in resource %s/%s", lineage, productName, resourceName)
 	}
 }
 
@@ -1009,6 +2908,39 @@ func (t *Type) GetIdFormat() string {
 	return t.ResourceMetadata.GetIdFormat()
 }
 
+// Returns the `{{name}}`-style token the property contributes to the
+// resource's import ID if it is an addressing field, that is a top-level
+// `url_param_only` field, a required field used in the resource's ID format or
+// a field of an `addressing_only` object, and an empty string otherwise. For
+// an `addressing_only` object, its children's tokens are joined by slashes.
+func (t Type) ImportIdSegment() string {
+	if t.ParentMetadata != nil {
+		// children of an addressing object are referenced through it
+		if t.ParentMetadata.AddressingOnly {
+			return fmt.Sprintf("{{%s.%s}}", google.Underscore(t.ParentMetadata.Name), google.Underscore(t.Name))
+		}
+		return ""
+	}
+
+	if t.AddressingOnly {
+		var tokens []string
+		for _, c := range t.Properties {
+			tokens = append(tokens, c.ImportIdSegment())
+		}
+		return strings.Join(tokens, "/")
+	}
+
+	token := fmt.Sprintf("{{%s}}", google.Underscore(t.Name))
+	if t.UrlParamOnly {
+		return token
+	}
+	if t.Required && t.ResourceMetadata != nil && strings.Contains(t.ResourceMetadata.GetIdFormat(), token) {
+		return token
+	}
+
+	return ""
+}
+
 func (t *Type) GoLiteral(value interface{}) string {
 	switch v := value.(type) {
 	case int:
@@ -1049,13 +2981,202 @@ func (t *Type) IsForceNew() bool {
 
 	parent := t.Parent()
 	return (!t.Output || t.IsA("KeyValueEffectiveLabels")) &&
-		(t.Immutable ||
+		(t.Immutable || t.CreateOnlyReadable ||
 			(t.ResourceMetadata.Immutable && t.UpdateUrl == "" &&
 				(parent == nil ||
 					(parent.IsForceNew() &&
 						!(parent.FlattenObject && t.IsA("KeyValueLabels"))))))
 }
 
+// Returns whether the property is only ever read from the API, never set by
+// the user: output fields, fingerprints and the effective and terraform
+// labels computed by the provider. The user-facing labels field also ignores
+// writes, as its value is sent through effective labels, but is configurable.
+func (t Type) IsReadOnly() bool {
+	if t.Output || t.IsA("Fingerprint") {
+		return true
+	}
+
+	return t.IgnoreWrite && (t.IsA("KeyValueEffectiveLabels") || t.IsA("KeyValueTerraformLabels"))
+}
+
+// Returns whether changing the property is applied in place by the update
+// function rather than being read-only or recreating the resource. Labels on a
+// resource with root labels are always updatable, consistent with IsForceNew.
+func (t Type) IsUpdatable() bool {
+	if t.IsA("KeyValueLabels") && t.ResourceMetadata.RootLabels() {
+		return true
+	}
+
+	if t.Output || t.UrlParamOnly || t.Immutable || t.CreateOnlyReadable {
+		return false
+	}
+
+	// Fields with their own update_url are updated through it, even on an
+	// otherwise immutable resource.
+	if t.UpdateUrl != "" {
+		return true
+	}
+
+	return !t.IsForceNew()
+}
+
+// Returns whether the update function should send an explicit null for the
+// field when the user removes its value, rather than omitting it.
+func (t Type) NeedsExplicitNullOnClear() bool {
+	return t.ClearWithNull && t.IsUpdatable()
+}
+
+// Checks that `clear_with_null` is only set on optional, updatable top-level
+// fields, where the update function can tell that the value was removed.
+func (t Type) validateClearWithNull(rName string) {
+	if !t.ClearWithNull {
+		return
+	}
+
+	if t.Required || t.Output {
+		validationFailure("Property %s in resource %s can only set `clear_with_null` if it is optional.", t.Lineage(), rName)
+	}
+	if !t.IsUpdatable() {
+		validationFailure("Property %s in resource %s can only set `clear_with_null` if it is updatable.", t.Lineage(), rName)
+	}
+	if t.ParentMetadata != nil || t.FlattenObject {
+		validationFailure("Property %s in resource %s can only set `clear_with_null` on a top-level field.", t.Lineage(), rName)
+	}
+	if t.SendEmptyValue || t.SendEmptyValueWhen != "" {
+		validationFailure("Property %s in resource %s cannot set both `clear_with_null` and `send_empty_value` or `send_empty_value_when`.", t.Lineage(), rName)
+	}
+}
+
+// Checks that a field suppressing its diff in a CustomizeDiff has a
+// `diff_suppress_func` and a diff that can be cleared at a fixed path.
+func (t Type) validateDiffSuppressInCustomizeDiff(rName string) {
+	if !t.DiffSuppressInCustomizeDiff {
+		return
+	}
+
+	if t.DiffSuppressFunc == "" {
+		validationFailure("Property %s in resource %s sets `diff_suppress_in_customize_diff` without a `diff_suppress_func`.", t.Lineage(), rName)
+	}
+	if !t.DefaultFromApi {
+		validationFailure("Property %s in resource %s can only set `diff_suppress_in_customize_diff` if it sets `default_from_api`.", t.Lineage(), rName)
+	}
+	// the diff is cleared by its path, which isn't known for array elements
+	if t.inArray() {
+		validationFailure("Property %s in resource %s cannot set `diff_suppress_in_customize_diff` inside an Array.", t.Lineage(), rName)
+	}
+}
+
+// Returns the deprecated fields generated for the aliases of the property.
+// Each is a copy of the property named after the alias, which conflicts with
+// the property and takes its value from the API.
+func (t Type) AliasProperties() []*Type {
+	var aliases []*Type
+	for _, alias := range t.Aliases {
+		a := t.DeepCopy(t.ResourceMetadata)
+		a.Name = alias
+		a.Aliases = nil
+		a.DeprecationMessage = fmt.Sprintf("`%s` is deprecated and will be removed in a future major release. Use `%s` instead.", alias, google.Underscore(t.Name))
+		a.DefaultValue = nil
+		a.DefaultFromApi = true
+		a.Conflicts = []string{google.Underscore(t.Name)}
+		a.AtLeastOneOf = nil
+		a.ExactlyOneOf = nil
+		a.RequiredWith = nil
+		aliases = append(aliases, a)
+	}
+	return aliases
+}
+
+// Returns the Go expression for the key that create and update read the
+// property from. For a property with aliases, this is whichever of its names
+// is set in the configuration.
+func (t Type) ConfigKey() string {
+	key := fmt.Sprintf("%q", google.Underscore(t.Name))
+	if len(t.Aliases) == 0 {
+		return key
+	}
+	for _, alias := range t.Aliases {
+		key = fmt.Sprintf("%s, %q", key, alias)
+	}
+	return fmt.Sprintf("tpgresource.AliasedKey(d, %s)", key)
+}
+
+// Checks that the aliases of a property are valid Terraform field names that
+// don't collide with the other fields of the resource, and that the property
+// can be left unset when the configuration uses an alias.
+func (t Type) validateAliases(rName string) {
+	if len(t.Aliases) == 0 {
+		return
+	}
+
+	if t.ParentMetadata != nil || t.FlattenObject {
+		validationFailure("Property %s in resource %s can only set `aliases` on a top-level field.", t.Lineage(), rName)
+	}
+	if t.Required || t.Output {
+		validationFailure("Property %s in resource %s can only set `aliases` if it is optional.", t.Lineage(), rName)
+	}
+	if t.IsSet || t.DefaultFromField != "" {
+		validationFailure("Property %s in resource %s cannot set `aliases` with `is_set` or `default_from_field`.", t.Lineage(), rName)
+	}
+
+	taken := []string{"id", "project", "region", "zone", "self_link"}
+	for _, p := range t.ResourceMetadata.AllUserProperties() {
+		taken = append(taken, google.Underscore(p.Name))
+		if p.Name != t.Name {
+			taken = append(taken, p.Aliases...)
+		}
+	}
+	for i, alias := range t.Aliases {
+		if !terraformFieldNameRegex.MatchString(alias) {
+			validationFailure("Property %s in resource %s has alias %q, which should be a snake_case Terraform field name.", t.Lineage(), rName, alias)
+		}
+		if slices.Contains(taken, alias) || slices.Contains(t.Aliases[:i], alias) {
+			validationFailure("Property %s in resource %s has alias %q, which collides with another field of the resource.", t.Lineage(), rName, alias)
+		}
+	}
+}
+
+// The types a field can have.
+var knownTypes = []string{"Boolean", "Double", "Integer", "String", "Time", "Quantity", "Enum", "ResourceRef", "NestedObject", "Array", "KeyValuePairs", "KeyValueLabels", "KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations", "Map", "Fingerprint"}
+
+// Checks that each of a field's schema version changes names a known type
+// and an older schema version of the resource.
+func (t Type) validateSchemaVersionChanges(rName string) {
+	if len(t.SchemaVersionChanges) == 0 {
+		return
+	}
+
+	if t.ParentMetadata != nil || t.FlattenObject {
+		validationFailure("Property %s in resource %s can only set `schema_version_changes` on a top-level field.", t.Lineage(), rName)
+	}
+
+	var versions []int
+	for _, change := range t.SchemaVersionChanges {
+		if !slices.Contains(knownTypes, change.Type) {
+			validationFailure("Property %s in resource %s has a schema version change to unknown type %q, which should be one of %v.", t.Lineage(), rName, change.Type, knownTypes)
+		}
+		if change.Version < 0 || change.Version >= t.ResourceMetadata.SchemaVersion {
+			validationFailure("Property %s in resource %s has a schema version change for version %d, which should be older than the resource's `schema_version` %d.", t.Lineage(), rName, change.Version, t.ResourceMetadata.SchemaVersion)
+		}
+		if slices.Contains(versions, change.Version) {
+			validationFailure("Property %s in resource %s has more than one schema version change for version %d.", t.Lineage(), rName, change.Version)
+		}
+		versions = append(versions, change.Version)
+	}
+}
+
+// Returns the key that fields are batched into update calls by: fields with
+// the same (verb, url, fingerprint, id) are sent in the same request, as
+// described on UpdateId. Returns an empty string if the field isn't updatable.
+func (t Type) UpdateGroupKey() string {
+	if !t.IsUpdatable() {
+		return ""
+	}
+
+	return strings.Join([]string{t.UpdateVerb, t.UpdateUrl, t.FingerprintName, t.UpdateId}, "|")
+}
+
 // Returns an updated path for a given Terraform field path (e.g.
 // 'a_field', 'parent_field.0.child_name'). Returns nil if the property
 // is not included in the resource's properties and removes keys that have
@@ -1064,14 +3185,20 @@ func (t *Type) IsForceNew() bool {
 // fields still need to be included, ie:
 // flattenedField > newParent > renameMe should be passed to this function as
 // flattened_field.0.new_parent.0.im_renamed
-// TODO(emilymye): Change format of input for
-// exactly_one_of/at_least_one_of/etc to use camelcase, MM properities and
-// convert to snake in this method
+// The camelcase MM form without indices, ie flattenedField.newParent.imRenamed,
+// is accepted as well and normalized to the same path.
 func (t *Type) GetPropertySchemaPath(schemaPath string) string {
 	nestedProps := t.ResourceMetadata.UserProperites()
 
+	var pnames []string
+	for _, pname := range strings.Split(schemaPath, ".") {
+		if pname != "0" {
+			pnames = append(pnames, google.Underscore(pname))
+		}
+	}
+
 	var pathTkns []string
-	for _, pname := range strings.Split(schemaPath, ".0.") {
+	for _, pname := range pnames {
 		camelPname := google.Camelize(pname, "lower")
 		index := slices.IndexFunc(nestedProps, func(p *Type) bool {
 			return p.Name == camelPname
@@ -1113,3 +3240,85 @@ func (t Type) GetPropertySchemaPathList(propertyList []string) []string {
 	}
 	return list
 }
+
+// Returns the plugin framework validators package for the property's
+// schema type, eg. stringvalidator. Nested objects are list blocks, as in the
+// SDK schema.
+func (t Type) FrameworkValidatorPackage() string {
+	switch t.SchemaType() {
+	case "schema.TypeBool":
+		return "boolvalidator"
+	case "schema.TypeInt":
+		return "int64validator"
+	case "schema.TypeFloat":
+		return "float64validator"
+	case "schema.TypeList":
+		return "listvalidator"
+	case "schema.TypeSet":
+		return "setvalidator"
+	case "schema.TypeMap":
+		return "mapvalidator"
+	}
+	return "stringvalidator"
+}
+
+// Returns the plugin framework path.Expression for a schema path, eg.
+// `path.MatchRoot("config").AtListIndex(0).AtName("size")` for
+// "config.0.size".
+func frameworkPathExpression(schemaPath string) string {
+	var b strings.Builder
+	for i, step := range strings.Split(schemaPath, ".") {
+		switch {
+		case i == 0:
+			fmt.Fprintf(&b, "path.MatchRoot(%q)", step)
+		case step == "0":
+			b.WriteString(".AtListIndex(0)")
+		default:
+			fmt.Fprintf(&b, ".AtName(%q)", step)
+		}
+	}
+	return b.String()
+}
+
+// Returns the plugin framework validators equivalent to the property's
+// `conflicts`, `exactly_one_of`, `at_least_one_of` and `required_with`, eg.
+// `stringvalidator.ConflictsWith(path.MatchRoot("other"))`, with the paths
+// resolved as for the SDK schema. The framework validators always include the
+// property itself, so it's left out of the paths. Groups scoped to the items
+// of an Array use paths relative to the item.
+func (t Type) FrameworkConstraintValidators() []string {
+	pkg := t.FrameworkValidatorPackage()
+	self := t.TerraformLineage()
+
+	var validators []string
+	add := func(name string, expressions []string) {
+		if len(expressions) > 0 {
+			validators = append(validators, fmt.Sprintf("%s.%s(%s)", pkg, name, strings.Join(expressions, ", ")))
+		}
+	}
+	expressions := func(list []string) []string {
+		var exprs []string
+		for _, path := range t.GetPropertySchemaPathList(list) {
+			if path != self {
+				exprs = append(exprs, frameworkPathExpression(path))
+			}
+		}
+		return exprs
+	}
+
+	add("ConflictsWith", expressions(t.Conflicting()))
+	if fields := t.ItemScopedExactlyOneOf(); fields != nil {
+		var exprs []string
+		for _, field := range fields {
+			if field != google.Underscore(t.Name) {
+				exprs = append(exprs, fmt.Sprintf("path.MatchRelative().AtParent().AtName(%q)", field))
+			}
+		}
+		add("ExactlyOneOf", exprs)
+	} else {
+		add("ExactlyOneOf", expressions(t.ExactlyOneOfList()))
+	}
+	add("AtLeastOneOf", expressions(t.AtLeastOneOfList()))
+	add("AlsoRequires", expressions(t.RequiredWithList()))
+	return validators
+}
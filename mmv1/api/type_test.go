@@ -1,12 +1,102 @@
 package api
 
 import (
+	"bytes"
+	"log"
+	"os"
+	"os/exec"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"golang.org/x/exp/slices"
 )
 
+// Validation problems are reported through log.Fatalf, so assertFatal runs fn
+// in a subprocess re-running only the current test, and fails unless that
+// subprocess exits with an error.
+func assertFatal(t *testing.T, fn func()) {
+	t.Helper()
+
+	if os.Getenv("MMV1_ASSERT_FATAL") == t.Name() {
+		fn()
+		os.Exit(0)
+	}
+
+	var pattern []string
+	for _, name := range strings.Split(t.Name(), "/") {
+		pattern = append(pattern, "^"+regexp.QuoteMeta(name)+"$")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run="+strings.Join(pattern, "/"))
+	cmd.Env = append(os.Environ(), "MMV1_ASSERT_FATAL="+t.Name())
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && !exitErr.Success() {
+			return
+		}
+		t.Fatalf("error running subprocess: %v", err)
+	}
+	t.Errorf("expected a fatal error")
+}
+
+// Renders the schema of prop with the SchemaFields template.
+func renderSchemaProperty(t *testing.T, prop *Type) string {
+	t.Helper()
+
+	tmpl, err := template.New("schema_property.go.tmpl").Funcs(google.TemplateFunctions).ParseFiles("../templates/terraform/schema_property.go.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents strings.Builder
+	if err := tmpl.ExecuteTemplate(&contents, "SchemaFields", prop); err != nil {
+		t.Fatal(err)
+	}
+	return contents.String()
+}
+
+// Renders the expand and flatten methods of prop with the named templates.
+func renderPropertyMethods(t *testing.T, prop *Type, names ...string) string {
+	t.Helper()
+
+	tmpl, err := template.New("").Funcs(google.TemplateFunctions).ParseFiles(
+		"../templates/terraform/expand_property_method.go.tmpl",
+		"../templates/terraform/flatten_property_method.go.tmpl",
+		"../templates/terraform/expand_resource_ref.tmpl",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents strings.Builder
+	for _, name := range names {
+		if err := tmpl.ExecuteTemplate(&contents, name, prop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return contents.String()
+}
+
+// Checks that the rendered schema got contains each of expected and none of
+// unexpected.
+func assertSchemaContains(t *testing.T, got string, expected, unexpected []string) {
+	t.Helper()
+
+	for _, s := range expected {
+		if !strings.Contains(got, s) {
+			t.Errorf("expected %q in schema, got:\n%s", s, got)
+		}
+	}
+	for _, s := range unexpected {
+		if strings.Contains(got, s) {
+			t.Errorf("unexpected %q in schema, got:\n%s", s, got)
+		}
+	}
+}
+
 func TestTypeMinVersionObj(t *testing.T) {
 	t.Parallel()
 
@@ -151,6 +241,32 @@ func TestTypeExcludeIfNotInVersion(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			description: "type with ExactVersions including the version",
+			obj: Type{
+				Name:          "test",
+				ExactVersions: []string{"alpha", "ga"},
+				ResourceMetadata: &Resource{
+					Name:            "test",
+					ProductMetadata: &p,
+				},
+			},
+			input:    p.Versions[1],
+			expected: false,
+		},
+		{
+			description: "type with ExactVersions excluding the version",
+			obj: Type{
+				Name:          "test",
+				ExactVersions: []string{"alpha", "ga"},
+				ResourceMetadata: &Resource{
+					Name:            "test",
+					ProductMetadata: &p,
+				},
+			},
+			input:    p.Versions[0],
+			expected: true,
+		},
 		{
 			description: "type has Exclude false and empty ExactVersion",
 			obj: Type{
@@ -184,3 +300,4306 @@ func TestTypeExcludeIfNotInVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeUserPropertiesInScope(t *testing.T) {
+	t.Parallel()
+
+	obj := Type{
+		Name: "parent",
+		Type: "NestedObject",
+		Properties: []*Type{
+			&Type{Name: "both", Type: "String"},
+			&Type{Name: "explicitBoth", Type: "String", Scope: "both"},
+			&Type{Name: "resourceOnly", Type: "String", Scope: "resource"},
+			&Type{Name: "datasourceOnly", Type: "String", Scope: "datasource"},
+			&Type{Name: "excluded", Type: "String", Scope: "datasource", Exclude: true},
+		},
+	}
+
+	cases := []struct {
+		description string
+		scope       string
+		expected    []string
+	}{
+		{
+			description: "resource scope",
+			scope:       SCOPE_RESOURCE,
+			expected:    []string{"both", "explicitBoth", "resourceOnly"},
+		},
+		{
+			description: "datasource scope",
+			scope:       SCOPE_DATASOURCE,
+			expected:    []string{"both", "explicitBoth", "datasourceOnly"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			var names []string
+			for _, p := range obj.UserPropertiesInScope(tc.scope) {
+				names = append(names, p.Name)
+			}
+
+			if got, want := names, tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+
+	t.Run("datasource scoped field is absent from the resource schema", func(t *testing.T) {
+		t.Parallel()
+
+		r := &Resource{Name: "topic"}
+		r.Properties = []*Type{
+			{Name: "name", Type: "String"},
+			{Name: "filter", Type: "String", Scope: "datasource"},
+			{Name: "policy", Type: "NestedObject", Properties: []*Type{
+				{Name: "regions", Type: "String"},
+				{Name: "pageToken", Type: "String", Scope: "datasource"},
+			}},
+		}
+		r.SetDefault(&Product{Name: "test"})
+
+		var got string
+		for _, p := range r.OrderProperties(r.AllUserProperties()) {
+			got += renderSchemaProperty(t, p)
+		}
+		for _, p := range r.SettableProperties() {
+			got += renderPropertyMethods(t, p, "expandPropertyMethod")
+		}
+		for _, p := range r.GettableProperties() {
+			got += renderPropertyMethods(t, p, "flattenPropertyMethod")
+		}
+
+		for _, want := range []string{`"name": {`, `"policy": {`, `"regions": {`, "expandtesttopicPolicyRegions", "flattentesttopicPolicyRegions"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected the resource to contain %s, got:\n%s", want, got)
+			}
+		}
+		for _, unwanted := range []string{"filter", "page_token", "PageToken"} {
+			if strings.Contains(got, unwanted) {
+				t.Errorf("expected the resource not to contain %s, got:\n%s", unwanted, got)
+			}
+		}
+	})
+}
+
+func TestTypeIsEffectivelyEmpty(t *testing.T) {
+	t.Parallel()
+
+	obj := Type{
+		Name: "parent",
+		Type: "NestedObject",
+		Properties: []*Type{
+			&Type{Name: "stringField", Type: "String"},
+			&Type{
+				Name: "child",
+				Type: "NestedObject",
+				Properties: []*Type{
+					&Type{Name: "intField", Type: "Integer"},
+				},
+			},
+			&Type{
+				Name:             "emptyAllowed",
+				Type:             "NestedObject",
+				AllowEmptyObject: true,
+				Properties: []*Type{
+					&Type{Name: "boolField", Type: "Boolean"},
+				},
+			},
+			&Type{
+				Name:    "entries",
+				Type:    "Map",
+				KeyName: "name",
+				ValueType: &Type{
+					Name: "entries",
+					Type: "NestedObject",
+					Properties: []*Type{
+						&Type{Name: "count", Type: "Integer"},
+						&Type{Name: "emptyAllowed", Type: "NestedObject", AllowEmptyObject: true},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		value       interface{}
+		expected    bool
+	}{
+		{
+			description: "nil",
+			value:       nil,
+			expected:    true,
+		},
+		{
+			description: "zero valued primitives",
+			value:       map[string]interface{}{"string_field": ""},
+			expected:    true,
+		},
+		{
+			description: "nested empty objects",
+			value: map[string]interface{}{
+				"string_field": "",
+				"child": []interface{}{
+					map[string]interface{}{"int_field": 0},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "nested nonempty object",
+			value: map[string]interface{}{
+				"child": []interface{}{
+					map[string]interface{}{"int_field": 1},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "nonempty primitive",
+			value:       map[string]interface{}{"stringField": "foo"},
+			expected:    false,
+		},
+		{
+			description: "nested object that allows empty objects",
+			value: map[string]interface{}{
+				"empty_allowed": []interface{}{
+					map[string]interface{}{"bool_field": false},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "map entry whose key matches a field name",
+			value: map[string]interface{}{
+				"entries": map[string]interface{}{
+					"emptyAllowed": map[string]interface{}{"count": 0},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "map entry with a value",
+			value: map[string]interface{}{
+				"entries": map[string]interface{}{
+					"a": map[string]interface{}{"count": 1},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := obj.IsEffectivelyEmpty(tc.value), tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeOmitsEffectivelyEmpty(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    bool
+	}{
+		{
+			description: "nested object from the API",
+			obj: Type{Name: "block", Type: "NestedObject", DefaultFromApi: true, Properties: []*Type{
+				{Name: "child", Type: "NestedObject", Properties: []*Type{{Name: "size", Type: "Integer"}}},
+			}},
+			expected: true,
+		},
+		{
+			description: "nested object from the API with an object that allows empty objects",
+			obj: Type{Name: "block", Type: "NestedObject", DefaultFromApi: true, Properties: []*Type{
+				{Name: "child", Type: "NestedObject", AllowEmptyObject: true, Properties: []*Type{{Name: "size", Type: "Integer"}}},
+			}},
+			expected: false,
+		},
+		{
+			description: "nested object from the API that allows empty objects",
+			obj:         Type{Name: "block", Type: "NestedObject", DefaultFromApi: true, AllowEmptyObject: true},
+			expected:    false,
+		},
+		{
+			description: "nested object",
+			obj:         Type{Name: "block", Type: "NestedObject"},
+			expected:    false,
+		},
+		{
+			description: "primitive from the API",
+			obj:         Type{Name: "size", Type: "Integer", DefaultFromApi: true},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.OmitsEffectivelyEmpty(), tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test"}
+	original := &Type{
+		Name:         "parent",
+		Type:         "NestedObject",
+		Conflicts:    []string{"other"},
+		ExactlyOneOf: []string{"parent", "other"},
+		Properties: []*Type{
+			&Type{
+				Name:     "list",
+				Type:     "Array",
+				ItemType: &Type{Type: "Enum", EnumValues: []string{"A", "B"}},
+			},
+			&Type{
+				Name:      "map",
+				Type:      "Map",
+				ValueType: &Type{Type: "NestedObject", Properties: []*Type{&Type{Name: "value", Type: "String"}}},
+			},
+		},
+		ResourceMetadata: r,
+	}
+	for _, p := range original.Properties {
+		p.ParentMetadata = original
+		p.ResourceMetadata = r
+	}
+	original.Properties[0].ItemType.ParentMetadata = original.Properties[0]
+	original.Properties[1].ValueType.ParentMetadata = original.Properties[1]
+
+	copied := &Resource{Name: "copied"}
+	c := original.DeepCopy(copied)
+
+	c.Exclude = true
+	c.Conflicts[0] = "changed"
+	c.ExactlyOneOf = append(c.ExactlyOneOf[:1], "changed")
+	c.Properties[0].Exclude = true
+	c.Properties[0].ItemType.EnumValues[0] = "C"
+	c.Properties[1].ValueType.Properties[0].Name = "changed"
+
+	if original.Exclude || original.Properties[0].Exclude {
+		t.Errorf("expected original properties to not be excluded")
+	}
+	if got, want := original.Conflicts, []string{"other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+	if got, want := original.ExactlyOneOf, []string{"parent", "other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+	if got, want := original.Properties[0].ItemType.EnumValues, []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+	if got, want := original.Properties[1].ValueType.Properties[0].Name, "value"; got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+
+	if c.Properties[0].ParentMetadata != c {
+		t.Errorf("expected copied property to be linked to the copied parent")
+	}
+	if c.Properties[0].ItemType.ParentMetadata != c.Properties[0] {
+		t.Errorf("expected copied item type to be linked to the copied array")
+	}
+	if c.Properties[1].ValueType.ParentMetadata != c.Properties[1] {
+		t.Errorf("expected copied value type to be linked to the copied map")
+	}
+	if c.ResourceMetadata == original.ResourceMetadata {
+		t.Errorf("expected copied property to be linked to the new resource")
+	}
+	for _, p := range []*Type{c, c.Properties[0], c.Properties[0].ItemType, c.Properties[1].ValueType.Properties[0]} {
+		if p.ResourceMetadata != copied {
+			t.Errorf("expected copied property %s to belong to the new resource", p.Name)
+		}
+	}
+	if original.ResourceMetadata != r || original.Properties[0].ResourceMetadata != r {
+		t.Errorf("expected original properties to keep their resource")
+	}
+}
+
+func TestTypeValidateImmutable(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{
+		Name:            "test",
+		UpdateVerb:      "PUT",
+		ProductMetadata: &Product{Name: "test"},
+	}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "immutable",
+			obj:         Type{Name: "test", Type: "String", Immutable: true, UpdateVerb: "PUT"},
+			fatal:       false,
+		},
+		{
+			description: "update_url without immutable",
+			obj:         Type{Name: "test", Type: "String", UpdateUrl: "test:update", UpdateVerb: "POST"},
+			fatal:       false,
+		},
+		{
+			description: "immutable with update_url",
+			obj:         Type{Name: "test", Type: "String", Immutable: true, UpdateUrl: "test:update"},
+			fatal:       true,
+		},
+		{
+			description: "immutable with update_verb",
+			obj:         Type{Name: "test", Type: "String", Immutable: true, UpdateVerb: "PATCH"},
+			fatal:       true,
+		},
+		{
+			description: "immutable with update_mask_fields",
+			obj:         Type{Name: "test", Type: "String", Immutable: true, UpdateMaskFields: []string{"test"}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeEnumValuesSlice(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		addEmpty    bool
+		expected    string
+	}{
+		{
+			description: "inlined enum_values",
+			obj:         Type{Type: "Enum", EnumValues: []string{"A", "B"}},
+			addEmpty:    true,
+			expected:    `[]string{"A", "B", ""}`,
+		},
+		{
+			description: "enum_values_from",
+			obj:         Type{Type: "Enum", EnumValuesFrom: "verify.SharedValues"},
+			addEmpty:    false,
+			expected:    "verify.SharedValues",
+		},
+		{
+			description: "enum_values_from with empty value",
+			obj:         Type{Type: "Enum", EnumValuesFrom: "verify.SharedValues"},
+			addEmpty:    true,
+			expected:    `append([]string{""}, verify.SharedValues...)`,
+		},
+		{
+			description: "required enum_values_from",
+			obj:         Type{Type: "Enum", EnumValuesFrom: "verify.SharedValues", Required: true},
+			addEmpty:    true,
+			expected:    "verify.SharedValues",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.EnumValuesSlice(tc.addEmpty), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeEnumValuesFromSchema(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	obj := &Type{Name: "mode", Type: "Enum", Description: "The mode.", EnumValuesFrom: "verify.SharedValues", ResourceMetadata: r}
+
+	got := renderSchemaProperty(t, obj)
+
+	if !strings.Contains(got, `ValidateFunc: verify.ValidateEnum(append([]string{""}, verify.SharedValues...))`) {
+		t.Errorf("expected a validation referencing enum_values_from, got:\n%s", got)
+	}
+	if strings.Count(got, "verify.SharedValues") != 1 || strings.Contains(got, "Possible values") {
+		t.Errorf("expected the description not to list enum_values_from, got:\n%s", got)
+	}
+}
+
+func TestTypeValidateEnumValuesFrom(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "enum_values_from",
+			obj:         Type{Name: "test", Type: "Enum", EnumValuesFrom: "verify.SharedValues"},
+			fatal:       false,
+		},
+		{
+			description: "enum_values and enum_values_from",
+			obj:         Type{Name: "test", Type: "Enum", EnumValues: []string{"A"}, EnumValuesFrom: "verify.SharedValues"},
+			fatal:       true,
+		},
+		{
+			description: "unqualified enum_values_from",
+			obj:         Type{Name: "test", Type: "Enum", EnumValuesFrom: "SharedValues"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDepth(t *testing.T) {
+	t.Parallel()
+
+	leaf := func() *Type {
+		return &Type{Name: "leaf", Type: "String"}
+	}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    int
+	}{
+		{
+			description: "primitive",
+			obj:         *leaf(),
+			expected:    1,
+		},
+		{
+			description: "array of primitives",
+			obj:         Type{Name: "list", Type: "Array", ItemType: &Type{Type: "String"}},
+			expected:    1,
+		},
+		{
+			description: "nested object",
+			obj: Type{
+				Name: "parent",
+				Type: "NestedObject",
+				Properties: []*Type{
+					leaf(),
+					&Type{Name: "child", Type: "NestedObject", Properties: []*Type{leaf()}},
+				},
+			},
+			expected: 3,
+		},
+		{
+			description: "array of nested objects",
+			obj: Type{
+				Name: "list",
+				Type: "Array",
+				ItemType: &Type{
+					Type:       "NestedObject",
+					Properties: []*Type{leaf()},
+				},
+			},
+			expected: 2,
+		},
+		{
+			description: "map of nested objects containing an array of nested objects",
+			obj: Type{
+				Name: "map",
+				Type: "Map",
+				ValueType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						leaf(),
+						&Type{
+							Name: "list",
+							Type: "Array",
+							ItemType: &Type{
+								Type:       "NestedObject",
+								Properties: []*Type{leaf()},
+							},
+						},
+					},
+				},
+			},
+			expected: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.Depth(), tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeSetDefaultExpandsGroups(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", UpdateVerb: "PUT"}
+	obj := Type{
+		Name: "parent",
+		Type: "NestedObject",
+		Properties: []*Type{
+			&Type{Name: "fieldA", Type: "String"},
+			&Type{Name: "fieldB", Type: "String"},
+			&Type{Name: "fieldC", Type: "String", ExactlyOneOf: []string{"parent.0.field_a"}},
+			&Type{Name: "fieldD", Type: "String"},
+		},
+		ExactlyOneOfGroups: [][]string{{"fieldA", "fieldB", "field_c"}},
+		AtLeastOneOfGroups: [][]string{{"fieldC", "fieldD"}},
+		RequiredWithGroups: [][]string{{"fieldA", "fieldD"}},
+	}
+
+	obj.SetDefault(r)
+
+	cases := []struct {
+		description string
+		got         []string
+		expected    []string
+	}{
+		{
+			description: "exactly_one_of",
+			got:         obj.Properties[0].ExactlyOneOf,
+			expected:    []string{"parent.0.field_a", "parent.0.field_b", "parent.0.field_c"},
+		},
+		{
+			description: "exactly_one_of merged with the manual form",
+			got:         obj.Properties[2].ExactlyOneOf,
+			expected:    []string{"parent.0.field_a", "parent.0.field_b", "parent.0.field_c"},
+		},
+		{
+			description: "at_least_one_of",
+			got:         obj.Properties[3].AtLeastOneOf,
+			expected:    []string{"parent.0.field_c", "parent.0.field_d"},
+		},
+		{
+			description: "required_with excludes the field itself",
+			got:         obj.Properties[0].RequiredWith,
+			expected:    []string{"parent.0.field_d"},
+		},
+		{
+			description: "non-members are unchanged",
+			got:         obj.Properties[3].ExactlyOneOf,
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.got, tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+
+	t.Run("unknown group member", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := Type{
+			Name:               "parent",
+			Type:               "NestedObject",
+			Properties:         []*Type{&Type{Name: "fieldA", Type: "String"}},
+			ExactlyOneOfGroups: [][]string{{"fieldA", "missing"}},
+			ResourceMetadata:   &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}},
+		}
+		for _, p := range invalid.Properties {
+			p.ResourceMetadata = invalid.ResourceMetadata
+		}
+
+		assertFatal(t, func() { invalid.Validate("test") })
+	})
+}
+
+func TestTypeDeclaredValidationLength(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "no length",
+			obj:         Type{Type: "String"},
+			expected:    "",
+		},
+		{
+			description: "max_length",
+			obj:         Type{Type: "String", MaxLength: 63},
+			expected:    "validation.StringLenBetween(0, 63)",
+		},
+		{
+			description: "min_length",
+			obj:         Type{Type: "String", MinLength: 1},
+			expected:    "validation.StringLenBetween(1, math.MaxInt)",
+		},
+		{
+			description: "min_length and max_length",
+			obj:         Type{Type: "String", MinLength: 1, MaxLength: 63},
+			expected:    "validation.StringLenBetween(1, 63)",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.DeclaredValidation(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeValidateLength(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "string with length bounds",
+			obj:         Type{Name: "test", Type: "String", MinLength: 1, MaxLength: 63},
+			fatal:       false,
+		},
+		{
+			description: "integer with length bounds",
+			obj:         Type{Name: "test", Type: "Integer", MaxLength: 63},
+			fatal:       true,
+		},
+		{
+			description: "min_length greater than max_length",
+			obj:         Type{Name: "test", Type: "String", MinLength: 64, MaxLength: 63},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDeclaredValidationPattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "pattern",
+			obj:         Type{Type: "String", Pattern: "^[a-z][-a-z0-9]{0,61}[a-z0-9]$"},
+			expected:    "validation.StringMatch(regexp.MustCompile(`^[a-z][-a-z0-9]{0,61}[a-z0-9]$`), \"must match the pattern ^[a-z][-a-z0-9]{0,61}[a-z0-9]$\")",
+		},
+		{
+			description: "pattern with message",
+			obj:         Type{Type: "String", Pattern: "^[a-z]+$", PatternMessage: "must be lowercase letters"},
+			expected:    "validation.StringMatch(regexp.MustCompile(`^[a-z]+$`), \"must be lowercase letters\")",
+		},
+		{
+			description: "pattern and length",
+			obj:         Type{Type: "String", Pattern: "^[a-z]+$", PatternMessage: "must be lowercase letters", MaxLength: 63},
+			expected:    "validation.All(validation.StringLenBetween(0, 63), validation.StringMatch(regexp.MustCompile(`^[a-z]+$`), \"must be lowercase letters\"))",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.DeclaredValidation(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeValidatePattern(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "valid pattern",
+			obj:         Type{Name: "test", Type: "String", Pattern: "^[a-z][-a-z0-9]{0,61}[a-z0-9]$"},
+			fatal:       false,
+		},
+		{
+			description: "invalid pattern",
+			obj:         Type{Name: "test", Type: "String", Pattern: "^[a-z"},
+			fatal:       true,
+		},
+		{
+			description: "pattern on a non-string",
+			obj:         Type{Name: "test", Type: "Boolean", Pattern: "^true$"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeFingerprintDependencies(t *testing.T) {
+	t.Parallel()
+
+	obj := Type{
+		Name: "root",
+		Type: "NestedObject",
+		Properties: []*Type{
+			&Type{Name: "labels", Type: "String", FingerprintName: "labelFingerprint"},
+			&Type{Name: "metadata", Type: "String", FingerprintName: "metadataFingerprint"},
+			&Type{
+				Name: "list",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						&Type{Name: "tags", Type: "String", FingerprintName: "labelFingerprint"},
+					},
+				},
+			},
+			&Type{Name: "excluded", Type: "String", FingerprintName: "excludedFingerprint", Exclude: true},
+			&Type{Name: "plain", Type: "String"},
+		},
+	}
+
+	if got, want := obj.FingerprintDependencies(), []string{"labelFingerprint", "metadataFingerprint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+}
+
+func TestTypeValidateNullable(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "nullable boolean",
+			obj:         Type{Name: "test", Type: "Boolean", Nullable: true},
+			fatal:       false,
+		},
+		{
+			description: "nullable double",
+			obj:         Type{Name: "test", Type: "Double", Nullable: true},
+			fatal:       false,
+		},
+		{
+			description: "nullable string",
+			obj:         Type{Name: "test", Type: "String", Nullable: true},
+			fatal:       true,
+		},
+		{
+			description: "nullable inside an array",
+			obj: Type{
+				Name:           "test",
+				Type:           "Boolean",
+				Nullable:       true,
+				ParentMetadata: &Type{Name: "parent", Type: "Array"},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDocExample(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "no example",
+			obj:         Type{Type: "String"},
+			expected:    "",
+		},
+		{
+			description: "string",
+			obj:         Type{Type: "String", Example: "my-name"},
+			expected:    `"my-name"`,
+		},
+		{
+			description: "integer",
+			obj:         Type{Type: "Integer", Example: "42"},
+			expected:    "42",
+		},
+		{
+			description: "string list",
+			obj: Type{
+				Type:     "Array",
+				ItemType: &Type{Type: "String"},
+				Example:  "a, b",
+			},
+			expected: `["a", "b"]`,
+		},
+		{
+			description: "integer list",
+			obj: Type{
+				Type:     "Array",
+				ItemType: &Type{Type: "Integer"},
+				Example:  "1,2",
+			},
+			expected: "[1, 2]",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.DocExample(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeImportIdSegment(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{IdFormat: "projects/{{project}}/topics/{{topic_name}}"}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "url_param_only field",
+			obj:         Type{Name: "location", UrlParamOnly: true, ResourceMetadata: r},
+			expected:    "{{location}}",
+		},
+		{
+			description: "required field in the id format",
+			obj:         Type{Name: "topicName", Required: true, ResourceMetadata: r},
+			expected:    "{{topic_name}}",
+		},
+		{
+			description: "required field not in the id format",
+			obj:         Type{Name: "description", Required: true, ResourceMetadata: r},
+			expected:    "",
+		},
+		{
+			description: "non-addressing field",
+			obj:         Type{Name: "topicName", ResourceMetadata: r},
+			expected:    "",
+		},
+		{
+			description: "nested field",
+			obj: Type{
+				Name:             "location",
+				UrlParamOnly:     true,
+				ResourceMetadata: r,
+				ParentMetadata:   &Type{Name: "parent"},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.ImportIdSegment(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeSchemaConfigModeAttr(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	child := &Type{Name: "child", Type: "String", ResourceMetadata: r}
+	item := &Type{Type: "NestedObject", Properties: []*Type{child}, ResourceMetadata: r}
+	list := &Type{Name: "list", Type: "Array", ItemType: item, SchemaConfigModeAttr: true, ResourceMetadata: r}
+	item.ParentMetadata = list
+	child.ParentMetadata = item
+
+	got := renderSchemaProperty(t, list)
+
+	configMode := strings.Index(got, "ConfigMode: schema.SchemaConfigModeAttr")
+	elem := strings.Index(got, "Elem:")
+	if configMode == -1 || elem == -1 || configMode > elem {
+		t.Errorf("expected ConfigMode to be set on the list schema, got:\n%s", got)
+	}
+	if strings.Count(got, "ConfigMode:") != 1 {
+		t.Errorf("expected ConfigMode to be set once, got:\n%s", got)
+	}
+}
+
+func TestTypeValidateSuppressNumericDiff(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "double",
+			obj:         Type{Name: "test", Type: "Double", SuppressNumericDiff: true},
+			fatal:       false,
+		},
+		{
+			description: "integer",
+			obj:         Type{Name: "test", Type: "Integer", SuppressNumericDiff: true},
+			fatal:       false,
+		},
+		{
+			description: "string",
+			obj:         Type{Name: "test", Type: "String", SuppressNumericDiff: true},
+			fatal:       true,
+		},
+		{
+			description: "with diff_suppress_func",
+			obj:         Type{Name: "test", Type: "Double", SuppressNumericDiff: true, DiffSuppressFunc: "tpgresource.CaseDiffSuppress"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateMapFields(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "map with key fields",
+			obj: Type{
+				Name:                "test",
+				Type:                "Map",
+				KeyName:             "name",
+				KeyDescription:      "The name.",
+				KeyExpander:         "tpgresource.ExpandString",
+				KeyDiffSuppressFunc: "tpgresource.CaseDiffSuppress",
+				ValueType:           &Type{Name: "value", Type: "NestedObject"},
+			},
+			fatal: false,
+		},
+		{
+			description: "map of objects",
+			obj:         Type{Name: "test", Type: "Map", KeyName: "key_id", ValueType: &Type{Name: "value", Type: "NestedObject"}},
+			fatal:       false,
+		},
+		{
+			description: "map of arrays",
+			obj:         Type{Name: "test", Type: "Map", KeyName: "name", ValueType: &Type{Name: "value", Type: "Array", ItemType: &Type{Type: "String"}}},
+			fatal:       true,
+		},
+		{
+			description: "map without value_type",
+			obj:         Type{Name: "test", Type: "Map", KeyName: "name"},
+			fatal:       true,
+		},
+		{
+			description: "map with camelCase key_name",
+			obj:         Type{Name: "test", Type: "Map", KeyName: "keyId", ValueType: &Type{Name: "value", Type: "NestedObject"}},
+			fatal:       true,
+		},
+		{
+			description: "map without key_name",
+			obj:         Type{Name: "test", Type: "Map", ValueType: &Type{Name: "value", Type: "NestedObject"}},
+			fatal:       true,
+		},
+		{
+			description: "string without key fields",
+			obj:         Type{Name: "test", Type: "String"},
+			fatal:       false,
+		},
+		{
+			description: "string with key_expander",
+			obj:         Type{Name: "test", Type: "String", KeyExpander: "tpgresource.ExpandString"},
+			fatal:       true,
+		},
+		{
+			description: "nested object with key_diff_suppress_func",
+			obj:         Type{Name: "test", Type: "NestedObject", KeyDiffSuppressFunc: "tpgresource.CaseDiffSuppress"},
+			fatal:       true,
+		},
+		{
+			description: "array with key_name",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "String"}, KeyName: "name"},
+			fatal:       true,
+		},
+		{
+			description: "key value pairs with key_description",
+			obj:         Type{Name: "test", Type: "KeyValuePairs", KeyDescription: "The name."},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			for _, child := range []*Type{tc.obj.ItemType, tc.obj.ValueType} {
+				if child != nil {
+					child.ResourceMetadata = r
+				}
+			}
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeSetDefaultKeyExpander(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "defaults to ExpandString",
+			obj:         Type{Name: "test", Type: "Map", ValueType: &Type{Name: "value", Type: "NestedObject"}},
+			expected:    "tpgresource.ExpandString",
+		},
+		{
+			description: "keeps an author-supplied expander",
+			obj:         Type{Name: "test", Type: "Map", KeyExpander: "expandCustomKey", ValueType: &Type{Name: "value", Type: "NestedObject"}},
+			expected:    "expandCustomKey",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.SetDefault(r)
+			if got, want := tc.obj.KeyExpander, tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeGetPropertySchemaPathList(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{
+			Name: "parentField",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "childName", Type: "String"},
+				{
+					Name:          "flattenedField",
+					Type:          "NestedObject",
+					FlattenObject: true,
+					Properties: []*Type{
+						{Name: "innerField", Type: "String"},
+					},
+				},
+			},
+		},
+		{Name: "topField", Type: "String"},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+	obj := Type{Name: "test", ResourceMetadata: r}
+
+	cases := []struct {
+		description string
+		camel       []string
+		snake       []string
+		expected    []string
+	}{
+		{
+			description: "top-level field",
+			camel:       []string{"topField"},
+			snake:       []string{"top_field"},
+			expected:    []string{"top_field"},
+		},
+		{
+			description: "nested field",
+			camel:       []string{"parentField.childName"},
+			snake:       []string{"parent_field.0.child_name"},
+			expected:    []string{"parent_field.0.child_name"},
+		},
+		{
+			description: "across a flatten_object boundary",
+			camel:       []string{"parentField.flattenedField.innerField"},
+			snake:       []string{"parent_field.0.flattened_field.0.inner_field"},
+			expected:    []string{"parent_field.0.inner_field"},
+		},
+		{
+			description: "missing field",
+			camel:       []string{"parentField.missing", "topField"},
+			snake:       []string{"parent_field.0.missing", "top_field"},
+			expected:    []string{"top_field"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := obj.GetPropertySchemaPathList(tc.camel), tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected camelcase paths %q to be %q", got, want)
+			}
+			if got, want := obj.GetPropertySchemaPathList(tc.snake), tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected snake_case paths %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeIsUpdatable(t *testing.T) {
+	t.Parallel()
+
+	mutable := &Resource{Name: "test"}
+	immutable := &Resource{Name: "test", Immutable: true}
+	rootLabels := &Resource{Name: "test", Immutable: true}
+	rootLabels.Properties = []*Type{{Name: "labels", Type: "KeyValueLabels", ResourceMetadata: rootLabels}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    bool
+	}{
+		{
+			description: "normal field",
+			obj:         Type{Name: "test", Type: "String", ResourceMetadata: mutable},
+			expected:    true,
+		},
+		{
+			description: "immutable field",
+			obj:         Type{Name: "test", Type: "String", Immutable: true, ResourceMetadata: mutable},
+			expected:    false,
+		},
+		{
+			description: "output field",
+			obj:         Type{Name: "test", Type: "String", Output: true, ResourceMetadata: mutable},
+			expected:    false,
+		},
+		{
+			description: "url_param_only field",
+			obj:         Type{Name: "test", Type: "String", UrlParamOnly: true, ResourceMetadata: mutable},
+			expected:    false,
+		},
+		{
+			description: "field on an immutable resource",
+			obj:         Type{Name: "test", Type: "String", ResourceMetadata: immutable},
+			expected:    false,
+		},
+		{
+			description: "field with an update_url on an immutable resource",
+			obj:         Type{Name: "test", Type: "String", UpdateUrl: "test:update", ResourceMetadata: immutable},
+			expected:    true,
+		},
+		{
+			description: "nested field",
+			obj: Type{
+				Name:             "test",
+				Type:             "String",
+				ResourceMetadata: mutable,
+				ParentMetadata:   &Type{Name: "parent", Type: "NestedObject", ResourceMetadata: mutable},
+			},
+			expected: true,
+		},
+		{
+			description: "nested field under an immutable parent",
+			obj: Type{
+				Name:             "test",
+				Type:             "String",
+				ResourceMetadata: immutable,
+				ParentMetadata:   &Type{Name: "parent", Type: "NestedObject", Immutable: true, ResourceMetadata: immutable},
+			},
+			expected: false,
+		},
+		{
+			description: "labels on a resource with root labels",
+			obj:         *rootLabels.Properties[0],
+			expected:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.IsUpdatable(), tc.expected; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeValidateReadPath(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "single key",
+			obj:         Type{Name: "test", Type: "String", ReadPath: "value"},
+			fatal:       false,
+		},
+		{
+			description: "dotted path",
+			obj:         Type{Name: "test", Type: "String", ReadPath: "wrapper.value"},
+			fatal:       false,
+		},
+		{
+			description: "invalid path",
+			obj:         Type{Name: "test", Type: "String", ReadPath: "wrapper..value"},
+			fatal:       true,
+		},
+		{
+			description: "with custom_flatten",
+			obj:         Type{Name: "test", Type: "String", ReadPath: "value", CustomFlatten: "templates/terraform/custom_flatten/name_from_self_link.tmpl"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeApiLineage(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test", ApiName: "test"}}
+	grandchild := &Type{Name: "grandchildField", ApiName: "grandchildApi", Type: "String"}
+	child := &Type{Name: "childField", ApiName: "childApi", Type: "NestedObject", Properties: []*Type{grandchild}}
+	parent := &Type{Name: "parentField", ApiName: "parentApi", Type: "NestedObject", Properties: []*Type{child}}
+	itemChild := &Type{Name: "itemField", ApiName: "itemApi", Type: "String"}
+	list := &Type{
+		Name:     "listField",
+		ApiName:  "listApi",
+		Type:     "Array",
+		ItemType: &Type{Type: "NestedObject", Properties: []*Type{itemChild}},
+	}
+	r.Properties = []*Type{parent, list}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	cases := []struct {
+		description string
+		obj         *Type
+		expected    string
+		key         string
+	}{
+		{
+			description: "renamed parent",
+			obj:         parent,
+			expected:    "parentApi",
+			key:         "parentApi",
+		},
+		{
+			description: "renamed grandchild under renamed parents",
+			obj:         grandchild,
+			expected:    "parentApi.childApi.grandchildApi",
+			key:         "grandchildApi",
+		},
+		{
+			description: "array item",
+			obj:         list.ItemType,
+			expected:    "listApi",
+			key:         "listApi",
+		},
+		{
+			description: "field in an array item",
+			obj:         itemChild,
+			expected:    "listApi.itemApi",
+			key:         "itemApi",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.ApiLineage(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+			if got, want := tc.obj.ApiKey(), tc.key; got != want {
+				t.Errorf("expected key %q to be %q", got, want)
+			}
+		})
+	}
+
+	// The generated expanders and flatteners use the api_name of each level
+	// for API keys and the name for Terraform keys, in both directions.
+	got := renderPropertyMethods(t, parent, "expandPropertyMethod", "flattenPropertyMethod")
+	for _, want := range []string{
+		`transformed["childApi"] =`,
+		`transformed["grandchildApi"] =`,
+		`original["child_field"]`,
+		`original["grandchild_field"]`,
+		`transformed["child_field"] =`,
+		`transformed["grandchild_field"] =`,
+		`original["childApi"]`,
+		`original["grandchildApi"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected generated code to contain %s, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestTypeValidateItemType(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "array of enums",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Enum", EnumValues: []string{"A"}}},
+			fatal:       false,
+		},
+		{
+			description: "array of integers",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Integer"}},
+			fatal:       false,
+		},
+		{
+			description: "array of maps",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Map"}},
+			fatal:       true,
+		},
+		{
+			description: "array without item_type",
+			obj:         Type{Name: "test", Type: "Array"},
+			fatal:       true,
+		},
+		{
+			description: "array of arrays of strings",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "String"}}},
+			fatal:       false,
+		},
+		{
+			description: "array of arrays of nested objects",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "NestedObject"}}},
+			fatal:       true,
+		},
+		{
+			description: "output array of arrays of nested objects",
+			obj:         Type{Name: "test", Type: "Array", Output: true, ItemType: &Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "NestedObject"}}},
+			fatal:       false,
+		},
+		{
+			description: "array of arrays of arrays",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "Array", ItemType: &Type{Name: "test", Type: "String"}}}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			for item := tc.obj.ItemType; item != nil; item = item.ItemType {
+				item.ResourceMetadata = r
+			}
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeSetDefaultFromApiRecursive(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	optional := &Type{Name: "optional", Type: "String"}
+	required := &Type{Name: "required", Type: "String", Required: true}
+	output := &Type{Name: "output", Type: "String", Output: true}
+	grandchild := &Type{Name: "grandchild", Type: "Integer"}
+	child := &Type{Name: "child", Type: "NestedObject", Properties: []*Type{grandchild}}
+	itemChild := &Type{Name: "itemChild", Type: "Boolean"}
+	list := &Type{Name: "list", Type: "Array", ItemType: &Type{Type: "NestedObject", Properties: []*Type{itemChild}}}
+	block := &Type{
+		Name:                    "block",
+		Type:                    "NestedObject",
+		DefaultFromApiRecursive: true,
+		Properties:              []*Type{optional, required, output, child, list},
+	}
+	block.SetDefault(r)
+
+	cases := []struct {
+		description string
+		obj         *Type
+		expected    bool
+	}{
+		{
+			description: "block",
+			obj:         block,
+			expected:    true,
+		},
+		{
+			description: "optional child",
+			obj:         optional,
+			expected:    true,
+		},
+		{
+			description: "required child",
+			obj:         required,
+			expected:    false,
+		},
+		{
+			description: "output child",
+			obj:         output,
+			expected:    false,
+		},
+		{
+			description: "nested block",
+			obj:         child,
+			expected:    true,
+		},
+		{
+			description: "field in a nested block",
+			obj:         grandchild,
+			expected:    true,
+		},
+		{
+			description: "field in an array item",
+			obj:         itemChild,
+			expected:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.DefaultFromApi, tc.expected; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeDefaultFromApiEmptyBlock(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	child := &Type{Name: "child", Type: "NestedObject", Properties: []*Type{{Name: "grandchild", Type: "Integer"}}}
+	block := &Type{
+		Name:                    "block",
+		Type:                    "NestedObject",
+		DefaultFromApiRecursive: true,
+		Properties:              []*Type{{Name: "size", Type: "Integer"}, child},
+	}
+	r.Properties = []*Type{block}
+	block.SetDefault(r)
+
+	got := renderSchemaProperty(t, block) + renderPropertyMethods(t, block, "expandPropertyMethod")
+
+	// Both blocks are Optional+Computed, and the nested block isn't sent when
+	// it's empty
+	for _, want := range []string{
+		"\"block\": {\n  Type: schema.TypeList,\nComputed: true,\n\tOptional: true,",
+		"\"child\": {\n  Type: schema.TypeList,\nComputed: true,\n\tOptional: true,",
+		"} else if !tpgresource.IsEffectivelyEmpty(transformedChild) {",
+		"} else if val := reflect.ValueOf(transformedSize); val.IsValid() && !tpgresource.IsEmptyValue(val) {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestTypeValidateForceNewIfSet(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "mutable field",
+			obj:         Type{Name: "test", Type: "String", ForceNewIfSet: true},
+			fatal:       false,
+		},
+		{
+			description: "immutable field",
+			obj:         Type{Name: "test", Type: "String", ForceNewIfSet: true, Immutable: true},
+			fatal:       true,
+		},
+		{
+			description: "output field",
+			obj:         Type{Name: "test", Type: "String", ForceNewIfSet: true, Output: true},
+			fatal:       true,
+		},
+		{
+			description: "inside an array",
+			obj: Type{
+				Name:           "test",
+				Type:           "String",
+				ForceNewIfSet:  true,
+				ParentMetadata: &Type{Name: "parent", Type: "Array"},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeGoZeroValue(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		goType      string
+		zeroValue   string
+	}{
+		{
+			description: "boolean",
+			obj:         Type{Type: "Boolean"},
+			goType:      "bool",
+			zeroValue:   "false",
+		},
+		{
+			description: "integer",
+			obj:         Type{Type: "Integer"},
+			goType:      "int",
+			zeroValue:   "0",
+		},
+		{
+			description: "double",
+			obj:         Type{Type: "Double"},
+			goType:      "float64",
+			zeroValue:   "0.0",
+		},
+		{
+			description: "string",
+			obj:         Type{Type: "String"},
+			goType:      "string",
+			zeroValue:   `""`,
+		},
+		{
+			description: "enum",
+			obj:         Type{Type: "Enum"},
+			goType:      "string",
+			zeroValue:   `""`,
+		},
+		{
+			description: "array",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "String"}},
+			goType:      "[]interface{}",
+			zeroValue:   "nil",
+		},
+		{
+			description: "map",
+			obj:         Type{Type: "Map"},
+			goType:      "map[string]interface{}",
+			zeroValue:   "nil",
+		},
+		{
+			description: "nested object",
+			obj:         Type{Type: "NestedObject"},
+			goType:      "map[string]interface{}",
+			zeroValue:   "nil",
+		},
+		{
+			description: "key value pairs",
+			obj:         Type{Type: "KeyValuePairs"},
+			goType:      "map[string]string",
+			zeroValue:   "nil",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.GoType(), tc.goType; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+			if got, want := tc.obj.GoZeroValue(), tc.zeroValue; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeSetHashExprGo(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		expr        string
+		expected    string
+	}{
+		{
+			description: "single field",
+			expr:        "{{.name}}",
+			expected:    `fmt.Sprintf("%v", raw["name"])`,
+		},
+		{
+			description: "several fields",
+			expr:        "{{.name}}-{{ .region }}",
+			expected:    `fmt.Sprintf("%v-%v", raw["name"], raw["region"])`,
+		},
+		{
+			description: "literal percent sign",
+			expr:        "{{.name}}%",
+			expected:    `fmt.Sprintf("%v%%", raw["name"])`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			obj := Type{SetHashExpr: tc.expr}
+			if got, want := obj.SetHashExprGo(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeValidateSetHashExpr(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	newSet := func(expr, hashFunc string) Type {
+		return Type{
+			Name:        "test",
+			Type:        "Array",
+			IsSet:       true,
+			SetHashExpr: expr,
+			SetHashFunc: hashFunc,
+			ItemType: &Type{
+				Name: "test",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "name", Type: "String"},
+					{Name: "sourceRegion", Type: "String"},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "existing fields",
+			obj:         newSet("{{.name}}-{{.source_region}}", ""),
+			fatal:       false,
+		},
+		{
+			description: "missing field",
+			obj:         newSet("{{.name}}-{{.region}}", ""),
+			fatal:       true,
+		},
+		{
+			description: "no fields",
+			obj:         newSet("name", ""),
+			fatal:       true,
+		},
+		{
+			description: "unsupported template syntax",
+			obj:         newSet("{{.name}}-{{ if .source_region }}", ""),
+			fatal:       true,
+		},
+		{
+			description: "with set_hash_func",
+			obj:         newSet("{{.name}}", "tpgresource.SelfLinkRelativePathHash"),
+			fatal:       true,
+		},
+		{
+			description: "not a set",
+			obj:         Type{Name: "test", Type: "String", SetHashExpr: "{{.name}}"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.validateSetHashExpr(r.Name) })
+			} else {
+				tc.obj.validateSetHashExpr(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateQuantity(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "quantity",
+			obj:         Type{Name: "test", Type: "Quantity"},
+			fatal:       false,
+		},
+		{
+			description: "quantity with enum_values",
+			obj:         Type{Name: "test", Type: "Quantity", EnumValues: []string{"1Gi", "2Gi"}},
+			fatal:       true,
+		},
+		{
+			description: "quantity with enum_values_from",
+			obj:         Type{Name: "test", Type: "Quantity", EnumValuesFrom: "sizes.Values"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeNestedArray(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	inner := &Type{Type: "Array", ItemType: &Type{Type: "Integer"}}
+	matrix := &Type{Name: "matrix", Type: "Array", ItemType: inner}
+	matrix.SetDefault(r)
+
+	if !matrix.IsNestedArray() {
+		t.Fatalf("expected %s to be a nested array", matrix.Name)
+	}
+	if inner.ItemType.Name != "matrix" || inner.ItemType.ParentMetadata != inner || inner.ItemType.ResourceMetadata != r {
+		t.Errorf("expected the inner item_type to be defaulted, got name %q", inner.ItemType.Name)
+	}
+
+	contents := renderSchemaProperty(t, matrix)
+	if got := strings.Join(strings.Fields(contents), " "); !strings.Contains(got, "Elem: &schema.Schema{ Type: schema.TypeList, Elem: &schema.Schema{ Type: schema.TypeInt, }, }") {
+		t.Errorf("expected a list of lists of integers, got:\n%s", contents)
+	}
+}
+
+func TestTypeSchemaQuantity(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    []string
+		unexpected  []string
+	}{
+		{
+			description: "quantity",
+			obj:         Type{Name: "memory", Type: "Quantity"},
+			expected: []string{
+				"Type: schema.TypeString",
+				"ValidateFunc: verify.ValidateQuantity",
+				"DiffSuppressFunc: tpgresource.QuantityDiffSuppress",
+			},
+		},
+		{
+			description: "output quantity",
+			obj:         Type{Name: "memory", Type: "Quantity", Output: true},
+			expected:    []string{"Type: schema.TypeString"},
+			unexpected:  []string{"ValidateFunc"},
+		},
+		{
+			description: "quantity with diff_suppress_func",
+			obj:         Type{Name: "memory", Type: "Quantity", DiffSuppressFunc: "tpgresource.CaseDiffSuppress"},
+			expected:    []string{"DiffSuppressFunc: tpgresource.CaseDiffSuppress"},
+			unexpected:  []string{"QuantityDiffSuppress"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			got := renderSchemaProperty(t, &tc.obj)
+			assertSchemaContains(t, got, tc.expected, tc.unexpected)
+		})
+	}
+}
+
+func TestTypeRequiredVersion(t *testing.T) {
+	t.Parallel()
+
+	p := Product{
+		Name: "test",
+		Versions: []*product.Version{
+			&product.Version{
+				Name:    "ga",
+				BaseUrl: "ga_url",
+			},
+			&product.Version{
+				Name:    "beta",
+				BaseUrl: "beta_url",
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		obj         Type
+		input       *product.Version
+		expected    bool
+	}{
+		{
+			description: "required_version beta generating beta",
+			obj:         Type{Name: "test", RequiredVersion: "beta"},
+			input:       &product.Version{Name: "beta"},
+			expected:    true,
+		},
+		{
+			description: "required_version beta generating ga",
+			obj:         Type{Name: "test", RequiredVersion: "beta"},
+			input:       &product.Version{Name: "ga"},
+			expected:    false,
+		},
+		{
+			description: "required_version ga generating beta",
+			obj:         Type{Name: "test", RequiredVersion: "ga"},
+			input:       &product.Version{Name: "beta"},
+			expected:    true,
+		},
+		{
+			description: "no required_version",
+			obj:         Type{Name: "test"},
+			input:       &product.Version{Name: "beta"},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = &Resource{Name: "test", ProductMetadata: &p}
+			tc.obj.ExcludeIfNotInVersion(tc.input)
+			if got, want := tc.obj.Required, tc.expected; got != want {
+				t.Errorf("expected Required to be %v, got %v", want, got)
+			}
+			if tc.obj.Exclude {
+				t.Errorf("expected %s not to be excluded", tc.obj.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateRequiredVersion(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "required_version",
+			obj:         Type{Name: "test", Type: "String", RequiredVersion: "beta"},
+			fatal:       false,
+		},
+		{
+			description: "required_version with required",
+			obj:         Type{Name: "test", Type: "String", RequiredVersion: "beta", Required: true},
+			fatal:       true,
+		},
+		{
+			description: "required_version with output",
+			obj:         Type{Name: "test", Type: "String", RequiredVersion: "beta", Output: true},
+			fatal:       true,
+		},
+		{
+			description: "unknown required_version",
+			obj:         Type{Name: "test", Type: "String", RequiredVersion: "preview"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeResourceRefTargets(t *testing.T) {
+	t.Parallel()
+
+	obj := Type{
+		Name: "root",
+		Type: "NestedObject",
+		Properties: []*Type{
+			&Type{Name: "network", Type: "ResourceRef", Resource: "Network"},
+			&Type{
+				Name: "interfaces",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						&Type{Name: "subnetwork", Type: "ResourceRef", Resource: "Subnetwork"},
+						&Type{Name: "network", Type: "ResourceRef", Resource: "Network"},
+					},
+				},
+			},
+			&Type{Name: "disks", Type: "Array", ItemType: &Type{Type: "ResourceRef", Resource: "Disk"}},
+			&Type{
+				Name: "routes",
+				Type: "Map",
+				ValueType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						&Type{Name: "nextHop", Type: "ResourceRef", Resource: "Route"},
+					},
+				},
+			},
+			&Type{Name: "excluded", Type: "ResourceRef", Resource: "Address", Exclude: true},
+			&Type{Name: "plain", Type: "String"},
+		},
+	}
+
+	if got, want := obj.ResourceRefTargets(), []string{"Network", "Subnetwork", "Disk", "Route"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+
+	if got := (Type{Name: "plain", Type: "String"}).ResourceRefTargets(); len(got) != 0 {
+		t.Errorf("expected no targets, got %v", got)
+	}
+}
+
+func TestTypeFlattenedChildrenSchema(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		parent      Type
+		child       Type
+		expected    []string
+		unexpected  []string
+	}{
+		{
+			description: "default_from_api child",
+			parent:      Type{Name: "settings", Type: "NestedObject", FlattenObject: true},
+			child:       Type{Name: "tier", Type: "String", DefaultFromApi: true},
+			expected:    []string{"Computed: true", "Optional: true"},
+		},
+		{
+			description: "default_from_api parent",
+			parent:      Type{Name: "settings", Type: "NestedObject", FlattenObject: true, DefaultFromApi: true},
+			child:       Type{Name: "tier", Type: "String"},
+			expected:    []string{"Computed: true", "Optional: true"},
+		},
+		{
+			description: "output parent",
+			parent:      Type{Name: "settings", Type: "NestedObject", FlattenObject: true, Output: true},
+			child:       Type{Name: "tier", Type: "String"},
+			expected:    []string{"Computed: true"},
+			unexpected:  []string{"Optional: true"},
+		},
+		{
+			description: "default_from_api parent with required child",
+			parent:      Type{Name: "settings", Type: "NestedObject", FlattenObject: true, DefaultFromApi: true},
+			child:       Type{Name: "tier", Type: "String", Required: true},
+			expected:    []string{"Required: true"},
+			unexpected:  []string{"Computed: true"},
+		},
+		{
+			description: "plain child",
+			parent:      Type{Name: "settings", Type: "NestedObject", FlattenObject: true},
+			child:       Type{Name: "tier", Type: "String"},
+			expected:    []string{"Optional: true"},
+			unexpected:  []string{"Computed: true"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.parent.Properties = []*Type{&tc.child}
+			r.Properties = []*Type{&tc.parent}
+			tc.parent.SetDefault(r)
+
+			root := r.RootProperties()
+			if len(root) != 1 || root[0] != &tc.child {
+				t.Fatalf("expected the child to be promoted to the root, got %v", root)
+			}
+
+			got := renderSchemaProperty(t, &tc.parent)
+			if !strings.Contains(got, `"tier": {`) {
+				t.Fatalf("expected the child in the schema, got:\n%s", got)
+			}
+			assertSchemaContains(t, got, tc.expected, tc.unexpected)
+		})
+	}
+}
+
+func TestTypeValidateResourceRefImports(t *testing.T) {
+	t.Parallel()
+
+	p := &Product{Name: "test"}
+	network := &Resource{Name: "Network", ProductMetadata: p, HasSelfLink: true}
+	network.Properties = []*Type{{Name: "name", Type: "String"}, {Name: "gatewayIpv4", Type: "String"}}
+	subnet := &Resource{Name: "Subnetwork", ProductMetadata: p}
+	subnet.Properties = []*Type{{Name: "name", Type: "String"}}
+	p.Objects = []*Resource{network, subnet}
+
+	r := &Resource{Name: "test", ProductMetadata: p}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "imports a property",
+			obj:         Type{Name: "network", Type: "ResourceRef", Resource: "Network", Imports: "gatewayIpv4"},
+			fatal:       false,
+		},
+		{
+			description: "imports selfLink with a self link",
+			obj:         Type{Name: "network", Type: "ResourceRef", Resource: "Network", Imports: "selfLink"},
+			fatal:       false,
+		},
+		{
+			description: "imports id",
+			obj:         Type{Name: "subnetwork", Type: "ResourceRef", Resource: "Subnetwork", Imports: "id"},
+			fatal:       false,
+		},
+		{
+			description: "resource not loaded",
+			obj:         Type{Name: "address", Type: "ResourceRef", Resource: "Address", Imports: "missing"},
+			fatal:       false,
+		},
+		{
+			description: "excluded reference",
+			obj:         Type{Name: "network", Type: "ResourceRef", Resource: "Network", Imports: "missing", Exclude: true},
+			fatal:       false,
+		},
+		{
+			description: "imports a missing property",
+			obj:         Type{Name: "network", Type: "ResourceRef", Resource: "Network", Imports: "gatewayIpv6"},
+			fatal:       true,
+		},
+		{
+			description: "imports selfLink without a self link",
+			obj:         Type{Name: "subnetwork", Type: "ResourceRef", Resource: "Subnetwork", Imports: "selfLink"},
+			fatal:       true,
+		},
+		{
+			description: "imports a missing property inside an array",
+			obj: Type{
+				Name: "interfaces",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						{Name: "network", Type: "ResourceRef", Resource: "Network", Imports: "nam"},
+					},
+				},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.validateResourceRefImports(r.Name) })
+			} else {
+				tc.obj.validateResourceRefImports(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDocDescription(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "enum",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode.\n", EnumValues: []string{"ON", "OFF"}},
+			expected:    "The mode.\nPossible values are: `ON`, `OFF`.",
+		},
+		{
+			description: "enum with default value",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode.", EnumValues: []string{"ON", "OFF"}, DefaultValue: "ON"},
+			expected:    "The mode.\nDefault value is `ON`.\nPossible values are: `ON`, `OFF`.",
+		},
+		{
+			description: "enum with exclude_docs_values",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode.", EnumValues: []string{"ON", "OFF"}, ExcludeDocsValues: true},
+			expected:    "The mode.",
+		},
+		{
+			description: "enum that already lists its values",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode. Possible values are ON or OFF.", EnumValues: []string{"ON", "OFF"}, DefaultValue: "ON"},
+			expected:    "The mode. Possible values are ON or OFF.\nDefault value is `ON`.\nPossible values are: `ON`, `OFF`.",
+		},
+		{
+			description: "enum with auto_enum_docs that already lists its values",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode. Possible values are ON or OFF.", EnumValues: []string{"ON", "OFF"}, DefaultValue: "ON", AutoEnumDocs: true},
+			expected:    "The mode. Possible values are ON or OFF.\nDefault value is `ON`.",
+		},
+		{
+			description: "enum with auto_enum_docs that lists some of its values",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode. Possible values include ON.", EnumValues: []string{"ON", "OFF"}, AutoEnumDocs: true},
+			expected:    "The mode. Possible values include ON.\nPossible values are: `ON`, `OFF`.",
+		},
+		{
+			description: "enum with auto_enum_docs that mentions a longer word",
+			obj:         Type{Name: "test", Type: "Enum", Description: "Turns the mode ON, or OFFLINE.", EnumValues: []string{"ON", "OFF"}, AutoEnumDocs: true},
+			expected:    "Turns the mode ON, or OFFLINE.\nPossible values are: `ON`, `OFF`.",
+		},
+		{
+			description: "array of enums with auto_enum_docs that already lists its values",
+			obj:         Type{Name: "test", Type: "Array", Description: "The modes, ON or OFF.", ItemType: &Type{Type: "Enum", EnumValues: []string{"ON", "OFF"}, AutoEnumDocs: true}},
+			expected:    "The modes, ON or OFF.",
+		},
+		{
+			description: "output enum",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode.", EnumValues: []string{"ON", "OFF"}, Output: true},
+			expected:    "The mode.",
+		},
+		{
+			description: "array of enums",
+			obj:         Type{Name: "test", Type: "Array", Description: "The modes.", ItemType: &Type{Type: "Enum", EnumValues: []string{"ON", "OFF"}}},
+			expected:    "The modes.\nEach value may be one of: `ON`, `OFF`.",
+		},
+		{
+			description: "enum_values_from",
+			obj:         Type{Name: "test", Type: "Enum", Description: "The mode.", EnumValuesFrom: "verify.SharedValues", DefaultValue: "ON"},
+			expected:    "The mode.\nDefault value is `ON`.",
+		},
+		{
+			description: "array of enums with enum_values_from",
+			obj:         Type{Name: "test", Type: "Array", Description: "The modes.", ItemType: &Type{Type: "Enum", EnumValuesFrom: "verify.SharedValues"}},
+			expected:    "The modes.",
+		},
+		{
+			description: "array of enums with exclude_docs_values",
+			obj:         Type{Name: "test", Type: "Array", Description: "The modes.", ItemType: &Type{Type: "Enum", EnumValues: []string{"ON", "OFF"}, ExcludeDocsValues: true}},
+			expected:    "The modes.",
+		},
+		{
+			description: "string",
+			obj:         Type{Name: "test", Type: "String", Description: "The name."},
+			expected:    "The name.",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.DocDescription(); got != tc.expected {
+				t.Errorf("expected %q to be %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTypeIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    bool
+	}{
+		{
+			description: "output field",
+			obj:         Type{Name: "createTime", Type: "String", Output: true},
+			expected:    true,
+		},
+		{
+			description: "fingerprint",
+			obj:         Type{Name: "fingerprint", Type: "Fingerprint"},
+			expected:    true,
+		},
+		{
+			description: "effective labels",
+			obj:         Type{Name: "effectiveLabels", Type: "KeyValueEffectiveLabels", IgnoreWrite: true},
+			expected:    true,
+		},
+		{
+			description: "terraform labels",
+			obj:         Type{Name: "terraformLabels", Type: "KeyValueTerraformLabels", IgnoreWrite: true},
+			expected:    true,
+		},
+		{
+			description: "labels written through effective labels",
+			obj:         Type{Name: "labels", Type: "KeyValueLabels", IgnoreWrite: true},
+			expected:    false,
+		},
+		{
+			description: "configurable field",
+			obj:         Type{Name: "name", Type: "String"},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.IsReadOnly(); got != tc.expected {
+				t.Errorf("expected %v to be %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTypeValidateDefaultFromField(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		field       string
+		properties  []*Type
+		fatal       bool
+	}{
+		{
+			description: "defaults from a sibling",
+			field:       "displayName",
+			properties: []*Type{
+				{Name: "name", Type: "String"},
+				{Name: "displayName", Type: "String", DefaultFromField: "name"},
+			},
+			fatal: false,
+		},
+		{
+			description: "defaults from a missing sibling",
+			field:       "displayName",
+			properties: []*Type{
+				{Name: "displayName", Type: "String", DefaultFromField: "name"},
+			},
+			fatal: true,
+		},
+		{
+			description: "defaults from itself",
+			field:       "displayName",
+			properties: []*Type{
+				{Name: "displayName", Type: "String", DefaultFromField: "displayName"},
+			},
+			fatal: true,
+		},
+		{
+			description: "two fields defaulting from each other",
+			field:       "displayName",
+			properties: []*Type{
+				{Name: "title", Type: "String", DefaultFromField: "displayName"},
+				{Name: "displayName", Type: "String", DefaultFromField: "title"},
+			},
+			fatal: true,
+		},
+		{
+			description: "required",
+			field:       "displayName",
+			properties: []*Type{
+				{Name: "name", Type: "String"},
+				{Name: "displayName", Type: "String", DefaultFromField: "name", Required: true},
+			},
+			fatal: true,
+		},
+		{
+			description: "nested field",
+			field:       "settings",
+			properties: []*Type{
+				{
+					Name: "settings",
+					Type: "NestedObject",
+					Properties: []*Type{
+						{Name: "name", Type: "String"},
+						{Name: "displayName", Type: "String", DefaultFromField: "name"},
+					},
+				},
+			},
+			fatal: true,
+		},
+		{
+			description: "field of a flattened object",
+			field:       "settings",
+			properties: []*Type{
+				{
+					Name:          "settings",
+					Type:          "NestedObject",
+					FlattenObject: true,
+					Properties: []*Type{
+						{Name: "name", Type: "String"},
+						{Name: "displayName", Type: "String", DefaultFromField: "name"},
+					},
+				},
+			},
+			fatal: false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}, Properties: tc.properties}
+			for _, p := range r.Properties {
+				p.SetDefault(r)
+			}
+			obj := r.Properties[slices.IndexFunc(r.Properties, func(p *Type) bool {
+				return p.Name == tc.field
+			})]
+			if tc.fatal {
+				assertFatal(t, func() { obj.Validate(r.Name) })
+			} else {
+				obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateSendEmptyValueWhen(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		field       string
+		properties  []*Type
+		fatal       bool
+	}{
+		{
+			description: "gated by a Boolean sibling",
+			field:       "description",
+			properties: []*Type{
+				{Name: "clearDescription", Type: "Boolean"},
+				{Name: "description", Type: "String", SendEmptyValueWhen: "clearDescription"},
+			},
+			fatal: false,
+		},
+		{
+			description: "gated by a missing sibling",
+			field:       "description",
+			properties: []*Type{
+				{Name: "description", Type: "String", SendEmptyValueWhen: "clearDescription"},
+			},
+			fatal: true,
+		},
+		{
+			description: "gated by a String sibling",
+			field:       "description",
+			properties: []*Type{
+				{Name: "clearDescription", Type: "String"},
+				{Name: "description", Type: "String", SendEmptyValueWhen: "clearDescription"},
+			},
+			fatal: true,
+		},
+		{
+			description: "with send_empty_value",
+			field:       "description",
+			properties: []*Type{
+				{Name: "clearDescription", Type: "Boolean"},
+				{Name: "description", Type: "String", SendEmptyValue: true, SendEmptyValueWhen: "clearDescription"},
+			},
+			fatal: true,
+		},
+		{
+			description: "within an array",
+			field:       "rules",
+			properties: []*Type{
+				{
+					Name: "rules",
+					Type: "Array",
+					ItemType: &Type{
+						Type: "NestedObject",
+						Properties: []*Type{
+							{Name: "clearDescription", Type: "Boolean"},
+							{Name: "description", Type: "String", SendEmptyValueWhen: "clearDescription"},
+						},
+					},
+				},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}, Properties: tc.properties}
+			for _, p := range r.Properties {
+				p.SetDefault(r)
+			}
+			obj := r.Properties[slices.IndexFunc(r.Properties, func(p *Type) bool {
+				return p.Name == tc.field
+			})]
+			if tc.fatal {
+				assertFatal(t, func() { obj.Validate(r.Name) })
+			} else {
+				obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDefaultFromFieldSchema(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String", Required: true},
+		{Name: "displayName", Type: "String", DefaultFromField: "name"},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	if got := r.DefaultFromFieldProperties(); len(got) != 1 || got[0].Name != "displayName" {
+		t.Fatalf("expected displayName to need a CustomizeDiff, got %v", got)
+	}
+	if source := r.Properties[1].DefaultFromFieldSource(); source != r.Properties[0] {
+		t.Errorf("expected displayName to default from name, got %v", source)
+	}
+
+	got := renderSchemaProperty(t, r.Properties[1])
+	if !strings.Contains(got, "Computed: true") || !strings.Contains(got, "Optional: true") {
+		t.Errorf("expected displayName to be Computed and Optional, got:\n%s", got)
+	}
+}
+
+func TestTypeSampleValue(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    interface{}
+	}{
+		{
+			description: "string",
+			obj:         Type{Name: "displayName", Type: "String"},
+			expected:    "sample-display_name",
+		},
+		{
+			description: "default value",
+			obj:         Type{Name: "tier", Type: "String", DefaultValue: "BASIC"},
+			expected:    "BASIC",
+		},
+		{
+			description: "enum",
+			obj:         Type{Name: "mode", Type: "Enum", EnumValues: []string{"ON", "OFF"}},
+			expected:    "ON",
+		},
+		{
+			description: "key value pairs",
+			obj:         Type{Name: "tags", Type: "KeyValuePairs"},
+			expected:    map[string]string{"key": "value"},
+		},
+		{
+			description: "output",
+			obj:         Type{Name: "createTime", Type: "String", Output: true},
+			expected:    nil,
+		},
+		{
+			description: "nested object",
+			obj: Type{
+				Name: "settings",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "enabled", Type: "Boolean"},
+					{Name: "state", Type: "String", Output: true},
+					{
+						Name: "backupConfig",
+						Type: "NestedObject",
+						Properties: []*Type{
+							{Name: "retentionDays", Type: "Integer", DefaultValue: 7},
+							{Name: "location", Type: "String"},
+						},
+					},
+					{Name: "zones", Type: "Array", ItemType: &Type{Type: "String"}},
+				},
+			},
+			expected: map[string]interface{}{
+				"enabled": true,
+				"backup_config": map[string]interface{}{
+					"retention_days": 7,
+					"location":       "sample-location",
+				},
+				"zones": []interface{}{"sample-zones"},
+			},
+		},
+		{
+			description: "array of nested objects",
+			obj: Type{
+				Name: "rules",
+				Type: "Array",
+				ItemType: &Type{
+					Type:       "NestedObject",
+					Properties: []*Type{{Name: "priority", Type: "Integer"}},
+				},
+			},
+			expected: []interface{}{map[string]interface{}{"priority": 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.SetDefault(r)
+			if got := tc.obj.SampleValue(); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %#v to be %#v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTypeValidateDeprecatedEnumValues(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "deprecated value in enum_values",
+			obj:         Type{Name: "test", Type: "Enum", EnumValues: []string{"NEW", "OLD"}, DeprecatedEnumValues: map[string]string{"OLD": "Use NEW instead."}},
+			fatal:       false,
+		},
+		{
+			description: "deprecated value not in enum_values",
+			obj:         Type{Name: "test", Type: "Enum", EnumValues: []string{"NEW"}, DeprecatedEnumValues: map[string]string{"OLD": "Use NEW instead."}},
+			fatal:       true,
+		},
+		{
+			description: "deprecated values on a string",
+			obj:         Type{Name: "test", Type: "String", DeprecatedEnumValues: map[string]string{"OLD": "Use NEW instead."}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDeprecatedEnumValuesSchema(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	obj := &Type{
+		Name:                 "mode",
+		Type:                 "Enum",
+		EnumValues:           []string{"NEW", "OLD", "OLDER"},
+		DeprecatedEnumValues: map[string]string{"OLDER": "Use \"NEW\" instead.", "OLD": "Use NEW instead."},
+		ResourceMetadata:     r,
+	}
+
+	want := `map[string]string{"OLD": "Use NEW instead.", "OLDER": "Use \"NEW\" instead."}`
+	if got := obj.DeprecatedEnumValuesLiteral(); got != want {
+		t.Errorf("expected %s to be %s", got, want)
+	}
+
+	c := obj.DeepCopy(r)
+	c.DeprecatedEnumValues["OLD"] = "changed"
+	if obj.DeprecatedEnumValues["OLD"] != "Use NEW instead." {
+		t.Errorf("expected the copy not to share deprecated values with the original")
+	}
+
+	got := renderSchemaProperty(t, obj)
+	if !strings.Contains(got, "ValidateFunc: verify.ValidateEnumWithDeprecations([]string{\"NEW\", \"OLD\", \"OLDER\", \"\"}, "+want+")") {
+		t.Errorf("expected a validation with deprecations, got:\n%s", got)
+	}
+	if strings.Count(got, "ValidateFunc:") != 1 {
+		t.Errorf("expected a single ValidateFunc, got:\n%s", got)
+	}
+}
+
+func TestTypeSchemaDescription(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		text        string
+		maxLen      int
+		expected    string
+	}{
+		{
+			description: "below the limit",
+			text:        "The name of the instance.",
+			maxLen:      30,
+			expected:    "The name of the instance.",
+		},
+		{
+			description: "exactly at the limit",
+			text:        "The name of the instance.",
+			maxLen:      25,
+			expected:    "The name of the instance.",
+		},
+		{
+			description: "above the limit",
+			text:        "The name of the instance.",
+			maxLen:      20,
+			expected:    "The name of the...",
+		},
+		{
+			description: "above the limit at the end of a word",
+			text:        "The name of the instance.",
+			maxLen:      18,
+			expected:    "The name of the...",
+		},
+		{
+			description: "single long word",
+			text:        "Supercalifragilistic",
+			maxLen:      10,
+			expected:    "Superca...",
+		},
+		{
+			description: "multibyte characters",
+			text:        "Le nom de l'équipe de données.",
+			maxLen:      21,
+			expected:    "Le nom de l'équipe...",
+		},
+		{
+			description: "multibyte characters in a cut word",
+			text:        "日本語の説明文です",
+			maxLen:      6,
+			expected:    "日本語...",
+		},
+		{
+			description: "no limit",
+			text:        "The name of the instance.\n",
+			maxLen:      0,
+			expected:    "The name of the instance.",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			obj := Type{Name: "test", Type: "String", Description: tc.text}
+			if got := obj.SchemaDescription(tc.maxLen); got != tc.expected {
+				t.Errorf("expected %q to be %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTypeUpdateGroupKey(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test"}
+	base := Type{Name: "a", Type: "String", UpdateVerb: "POST", UpdateUrl: "test:update", UpdateId: "a", FingerprintName: "fingerprint", ResourceMetadata: r}
+
+	cases := []struct {
+		description string
+		obj         Type
+		same        bool
+	}{
+		{
+			description: "same verb, url, id and fingerprint",
+			obj:         Type{Name: "b", Type: "Integer", UpdateVerb: "POST", UpdateUrl: "test:update", UpdateId: "a", FingerprintName: "fingerprint", ResourceMetadata: r},
+			same:        true,
+		},
+		{
+			description: "different verb",
+			obj:         Type{Name: "b", Type: "String", UpdateVerb: "PATCH", UpdateUrl: "test:update", UpdateId: "a", FingerprintName: "fingerprint", ResourceMetadata: r},
+			same:        false,
+		},
+		{
+			description: "different url",
+			obj:         Type{Name: "b", Type: "String", UpdateVerb: "POST", UpdateUrl: "test:setB", UpdateId: "a", FingerprintName: "fingerprint", ResourceMetadata: r},
+			same:        false,
+		},
+		{
+			description: "different id",
+			obj:         Type{Name: "b", Type: "String", UpdateVerb: "POST", UpdateUrl: "test:update", UpdateId: "b", FingerprintName: "fingerprint", ResourceMetadata: r},
+			same:        false,
+		},
+		{
+			description: "different fingerprint",
+			obj:         Type{Name: "b", Type: "String", UpdateVerb: "POST", UpdateUrl: "test:update", UpdateId: "a", FingerprintName: "labelFingerprint", ResourceMetadata: r},
+			same:        false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.UpdateGroupKey() == base.UpdateGroupKey(); got != tc.same {
+				t.Errorf("expected keys %q and %q to match: %v", tc.obj.UpdateGroupKey(), base.UpdateGroupKey(), tc.same)
+			}
+		})
+	}
+
+	immutable := Type{Name: "c", Type: "String", Immutable: true, UpdateVerb: "POST", UpdateUrl: "test:update", ResourceMetadata: r}
+	if got := immutable.UpdateGroupKey(); got != "" {
+		t.Errorf("expected no key for an immutable field, got %q", got)
+	}
+}
+
+func TestTypeComputedChildrenOfOptionalBlock(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", Immutable: true, ProductMetadata: &Product{Name: "test"}}
+	block := &Type{
+		Name: "block",
+		Type: "NestedObject",
+		Properties: []*Type{
+			{Name: "required", Type: "String", Required: true},
+			{Name: "optional", Type: "String"},
+			{Name: "computed", Type: "String", Output: true},
+			{Name: "serverDefault", Type: "String", DefaultFromApi: true},
+		},
+	}
+	r.Properties = []*Type{block}
+	block.SetDefault(r)
+
+	cases := []struct {
+		description string
+		field       string
+		expected    []string
+		unexpected  []string
+		forceNew    bool
+	}{
+		{
+			description: "required child",
+			field:       "required",
+			expected:    []string{"Required: true"},
+			unexpected:  []string{"Computed: true", "Optional: true"},
+			forceNew:    true,
+		},
+		{
+			description: "optional child",
+			field:       "optional",
+			expected:    []string{"Optional: true"},
+			unexpected:  []string{"Computed: true", "Required: true"},
+			forceNew:    true,
+		},
+		{
+			description: "output child",
+			field:       "computed",
+			expected:    []string{"Computed: true"},
+			unexpected:  []string{"Optional: true", "ForceNew: true"},
+			forceNew:    false,
+		},
+		{
+			description: "default_from_api child",
+			field:       "serverDefault",
+			expected:    []string{"Computed: true", "Optional: true"},
+			forceNew:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			child := block.Properties[slices.IndexFunc(block.Properties, func(p *Type) bool {
+				return p.Name == tc.field
+			})]
+			if got := child.IsForceNew(); got != tc.forceNew {
+				t.Errorf("expected IsForceNew to be %v, got %v", tc.forceNew, got)
+			}
+
+			got := renderSchemaProperty(t, child)
+			assertSchemaContains(t, got, tc.expected, tc.unexpected)
+		})
+	}
+}
+
+func TestTypeValidateSendEmptyValue(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "optional field",
+			obj:         Type{Name: "enabled", Type: "Boolean", SendEmptyValue: true},
+			fatal:       false,
+		},
+		{
+			description: "output field",
+			obj:         Type{Name: "enabled", Type: "Boolean", SendEmptyValue: true, Output: true},
+			fatal:       true,
+		},
+		{
+			description: "url_param_only field",
+			obj:         Type{Name: "enabled", Type: "Boolean", SendEmptyValue: true, UrlParamOnly: true},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.ResourceMetadata = r
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeAddressingOnly(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	target := &Type{
+		Name:           "target",
+		Type:           "NestedObject",
+		AddressingOnly: true,
+		Properties: []*Type{
+			{Name: "location", Type: "String", Required: true},
+			{Name: "name", Type: "String", Required: true},
+		},
+	}
+	r.Properties = []*Type{target, {Name: "description", Type: "String"}}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+	target.Validate(r.Name)
+
+	for _, c := range target.Properties {
+		if !c.UrlParamOnly {
+			t.Errorf("expected %s to be url_param_only", c.Name)
+		}
+	}
+	if slices.Contains(r.SettableProperties(), target) || slices.Contains(r.GettableProperties(), target) {
+		t.Errorf("expected %s to be excluded from the request body and read", target.Name)
+	}
+
+	if got, want := target.ImportIdSegment(), "{{target.location}}/{{target.name}}"; got != want {
+		t.Errorf("expected import id segment %q, got %q", want, got)
+	}
+	if got, want := google.Format2Regex("locations/{{target.location}}/targets/{{target.name}}"), "locations/(?P<target__location>[^/]+)/targets/(?P<target__name>[^/]+)"; got != want {
+		t.Errorf("expected import id regex %q, got %q", want, got)
+	}
+	formats := ImportIdFormats([]string{"projects/{{project}}/locations/{{target.location}}/targets/{{target.name}}"}, nil, "")
+	if !slices.Contains(formats, "{{target.location}}/{{target.name}}") {
+		t.Errorf("expected a short import id format for the addressing fields, got %v", formats)
+	}
+}
+
+func TestTypeValidateAddressingOnly(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         *Type
+		parent      bool
+		fatal       bool
+	}{
+		{
+			description: "primitive children",
+			obj: &Type{Name: "target", Type: "NestedObject", AddressingOnly: true, Properties: []*Type{
+				{Name: "name", Type: "String"},
+				{Name: "number", Type: "Integer"},
+			}},
+			fatal: false,
+		},
+		{
+			description: "nested object child",
+			obj: &Type{Name: "target", Type: "NestedObject", AddressingOnly: true, Properties: []*Type{
+				{Name: "name", Type: "NestedObject", Properties: []*Type{{Name: "id", Type: "String"}}},
+			}},
+			fatal: true,
+		},
+		{
+			description: "array child",
+			obj: &Type{Name: "target", Type: "NestedObject", AddressingOnly: true, Properties: []*Type{
+				{Name: "names", Type: "Array", ItemType: &Type{Type: "String"}},
+			}},
+			fatal: true,
+		},
+		{
+			description: "not a nested object",
+			obj:         &Type{Name: "target", Type: "String", AddressingOnly: true},
+			fatal:       true,
+		},
+		{
+			description: "nested within an object",
+			obj: &Type{Name: "target", Type: "NestedObject", AddressingOnly: true, Properties: []*Type{
+				{Name: "name", Type: "String"},
+			}},
+			parent: true,
+			fatal:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			obj := tc.obj
+			if tc.parent {
+				parent := &Type{Name: "parent", Type: "NestedObject", Properties: []*Type{tc.obj}}
+				parent.SetDefault(r)
+			} else {
+				obj.SetDefault(r)
+			}
+			if tc.fatal {
+				assertFatal(t, func() { obj.Validate(r.Name) })
+			} else {
+				obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateClearWithNull(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		immutable   bool
+		fatal       bool
+	}{
+		{
+			description: "optional updatable field",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true},
+			fatal:       false,
+		},
+		{
+			description: "required field",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true, Required: true},
+			fatal:       true,
+		},
+		{
+			description: "output field",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true, Output: true},
+			fatal:       true,
+		},
+		{
+			description: "immutable field",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true, Immutable: true},
+			fatal:       true,
+		},
+		{
+			description: "field on an immutable resource",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true},
+			immutable:   true,
+			fatal:       true,
+		},
+		{
+			description: "with send_empty_value",
+			obj:         Type{Name: "description", Type: "String", ClearWithNull: true, SendEmptyValue: true},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", Immutable: tc.immutable, ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+				if !tc.obj.NeedsExplicitNullOnClear() {
+					t.Errorf("expected %s to need an explicit null on clear", tc.obj.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeValidateDiffSuppressInCustomizeDiff(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "with diff_suppress_func and default_from_api",
+			obj:         Type{Name: "size", Type: "Integer", DiffSuppressFunc: "sizeDiffSuppress", DiffSuppressInCustomizeDiff: true, DefaultFromApi: true},
+			fatal:       false,
+		},
+		{
+			description: "without diff_suppress_func",
+			obj:         Type{Name: "size", Type: "Integer", DiffSuppressInCustomizeDiff: true, DefaultFromApi: true},
+			fatal:       true,
+		},
+		{
+			description: "without default_from_api",
+			obj:         Type{Name: "size", Type: "Integer", DiffSuppressFunc: "sizeDiffSuppress", DiffSuppressInCustomizeDiff: true},
+			fatal:       true,
+		},
+		{
+			description: "inside an array",
+			obj: Type{
+				Name: "disks",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						{Name: "size", Type: "Integer", DiffSuppressFunc: "sizeDiffSuppress", DiffSuppressInCustomizeDiff: true, DefaultFromApi: true},
+					},
+				},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeDiffSuppressInCustomizeDiff(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "autoResize", Type: "Boolean"},
+		{Name: "size", Type: "Integer", DiffSuppressFunc: "sizeDiffSuppress", DiffSuppressInCustomizeDiff: true, DefaultFromApi: true},
+		{Name: "name", Type: "String", DiffSuppressFunc: "nameDiffSuppress"},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	props := r.CustomizeDiffSuppressProperties()
+	if len(props) != 1 || props[0].Name != "size" {
+		t.Fatalf("expected only size to be suppressed in the CustomizeDiff, got %v", props)
+	}
+
+	for _, tc := range []struct {
+		prop   *Type
+		inline bool
+	}{
+		{prop: r.Properties[1], inline: false},
+		{prop: r.Properties[2], inline: true},
+	} {
+		contents := renderSchemaProperty(t, tc.prop)
+		if got := strings.Contains(contents, "DiffSuppressFunc: "+tc.prop.DiffSuppressFunc); got != tc.inline {
+			t.Errorf("expected inline DiffSuppressFunc on %s to be %t, got:\n%s", tc.prop.Name, tc.inline, contents)
+		}
+	}
+}
+
+func TestTypeValidateFilterable(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "string",
+			obj:         Type{Name: "name", Type: "String", Filterable: true},
+			fatal:       false,
+		},
+		{
+			description: "boolean",
+			obj:         Type{Name: "enabled", Type: "Boolean", Filterable: true},
+			fatal:       false,
+		},
+		{
+			description: "output string",
+			obj:         Type{Name: "name", Type: "String", Filterable: true, Output: true},
+			fatal:       true,
+		},
+		{
+			description: "nested object",
+			obj:         Type{Name: "network", Type: "NestedObject", Filterable: true, Properties: []*Type{{Name: "subnet", Type: "String"}}},
+			fatal:       true,
+		},
+		{
+			description: "array",
+			obj:         Type{Name: "tags", Type: "Array", Filterable: true, ItemType: &Type{Type: "String"}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateScope(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "default",
+			obj:         Type{Name: "name", Type: "String"},
+			fatal:       false,
+		},
+		{
+			description: "resource",
+			obj:         Type{Name: "name", Type: "String", Scope: "resource"},
+			fatal:       false,
+		},
+		{
+			description: "both",
+			obj:         Type{Name: "name", Type: "String", Scope: "both"},
+			fatal:       false,
+		},
+		{
+			description: "datasource",
+			obj:         Type{Name: "filter", Type: "String", Scope: "datasource"},
+			fatal:       false,
+		},
+		{
+			description: "unknown",
+			obj:         Type{Name: "name", Type: "String", Scope: "provider"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateSchemaVersionChanges(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "string to array",
+			obj:         Type{Name: "regions", Type: "Array", ItemType: &Type{Type: "String"}, SchemaVersionChanges: []SchemaVersionChange{{Version: 0, Type: "String"}}},
+			fatal:       false,
+		},
+		{
+			description: "unknown type",
+			obj:         Type{Name: "regions", Type: "Array", ItemType: &Type{Type: "String"}, SchemaVersionChanges: []SchemaVersionChange{{Version: 0, Type: "List"}}},
+			fatal:       true,
+		},
+		{
+			description: "current schema version",
+			obj:         Type{Name: "regions", Type: "Array", ItemType: &Type{Type: "String"}, SchemaVersionChanges: []SchemaVersionChange{{Version: 1, Type: "String"}}},
+			fatal:       true,
+		},
+		{
+			description: "repeated version",
+			obj:         Type{Name: "regions", Type: "Array", ItemType: &Type{Type: "String"}, SchemaVersionChanges: []SchemaVersionChange{{Version: 0, Type: "String"}, {Version: 0, Type: "Integer"}}},
+			fatal:       true,
+		},
+		{
+			description: "nested field",
+			obj: Type{
+				Name: "config",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "regions", Type: "Array", ItemType: &Type{Type: "String"}, SchemaVersionChanges: []SchemaVersionChange{{Version: 0, Type: "String"}}},
+				},
+			},
+			fatal: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", SchemaVersion: 1, ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateExactVersions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "exact_versions",
+			obj:         Type{Name: "test", Type: "String", ExactVersions: []string{"alpha", "ga"}},
+			fatal:       false,
+		},
+		{
+			description: "exact_version and exact_versions",
+			obj:         Type{Name: "test", Type: "String", ExactVersion: "beta", ExactVersions: []string{"alpha", "ga"}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeFunctionNames(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "Topic", ProductMetadata: &Product{Name: "Pubsub"}}
+	policy := &Type{Name: "messageStoragePolicy", Type: "String"}
+	glob := &Type{Name: "glob", Type: "String"}
+	rules := &Type{Name: "rules", Type: "Array", ItemType: &Type{Type: "NestedObject", Properties: []*Type{glob}}}
+	for _, p := range []*Type{policy, rules} {
+		p.SetDefault(r)
+	}
+
+	cases := []struct {
+		description string
+		obj         *Type
+		expand      string
+		flatten     string
+	}{
+		{
+			description: "top-level field",
+			obj:         policy,
+			expand:      "expandPubsubTopicMessageStoragePolicy",
+			flatten:     "flattenPubsubTopicMessageStoragePolicy",
+		},
+		{
+			description: "field under an array of objects",
+			obj:         glob,
+			expand:      "expandPubsubTopicRulesGlob",
+			flatten:     "flattenPubsubTopicRulesGlob",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.ExpandFunctionName(); got != tc.expand {
+				t.Errorf("expected expand function %s, got %s", tc.expand, got)
+			}
+			if got := tc.obj.FlattenFunctionName(); got != tc.flatten {
+				t.Errorf("expected flatten function %s, got %s", tc.flatten, got)
+			}
+		})
+	}
+}
+
+// Not parallel, as strict mode is shared by the package.
+func TestTypeStrictValidation(t *testing.T) {
+	SetStrictValidation(true)
+	defer SetStrictValidation(false)
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	obj := &Type{
+		Name: "config",
+		Type: "NestedObject",
+		Properties: []*Type{
+			{Name: "mode", Type: "Enum", Output: true, Required: true},
+			{Name: "size", Type: "Integer", DefaultValue: "10"},
+			{Name: "network", Type: "ResourceRef"},
+			{Name: "rules", Type: "Array", IsSet: true, ItemType: &Type{Type: "NestedObject"}},
+		},
+	}
+	obj.SetDefault(r)
+	obj.Validate(r.Name)
+
+	failures := ValidationFailures()
+	for _, expected := range []string{
+		"cannot be output and required",
+		"is an Enum without `enum_values`",
+		"has a `default_value` of type string",
+		"is a ResourceRef without a `resource` and `imports`",
+		"is a set of objects without a `set_hash_func` or `set_hash_expr`",
+	} {
+		if !slices.ContainsFunc(failures, func(f string) bool { return strings.Contains(f, expected) }) {
+			t.Errorf("expected a failure containing %q, got %v", expected, failures)
+		}
+	}
+	if len(failures) != 5 {
+		t.Errorf("expected 5 failures, got %d: %v", len(failures), failures)
+	}
+}
+
+func TestTypeSensitiveOutput(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	token := &Type{Name: "accessToken", Type: "String", Output: true, Sensitive: true}
+	token.SetDefault(r)
+	r.Properties = []*Type{token}
+
+	got := renderSchemaProperty(t, token)
+	for _, expected := range []string{"Computed: true", "Sensitive: true"} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected the schema to contain %q, got:\n%s", expected, got)
+		}
+	}
+	if strings.Contains(got, "Optional:") {
+		t.Errorf("expected an output-only schema, got:\n%s", got)
+	}
+
+	if !r.HasSensitiveOutputs() {
+		t.Errorf("expected %s to have sensitive outputs", r.Name)
+	}
+}
+
+func TestTypeLooksLikeSecret(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"accessToken":  true,
+		"sharedSecret": true,
+		"webhookKey":   true,
+		"key":          true,
+		"publicKey":    false,
+		"kmsKey":       false,
+		"kmsKeyName":   false,
+		"tokenStatus":  false,
+		"name":         false,
+	}
+
+	if (Type{Name: "primaryKey", Type: "Boolean"}).looksLikeSecret() {
+		t.Errorf("expected a Boolean not to look like a secret")
+	}
+	parent := &Type{Name: "publicKey", Type: "NestedObject"}
+	if (Type{Name: "key", Type: "String", ParentMetadata: parent}).looksLikeSecret() {
+		t.Errorf("expected the key of a public key not to look like a secret")
+	}
+	for name, expected := range cases {
+		if got := (Type{Name: name, Type: "String"}).looksLikeSecret(); got != expected {
+			t.Errorf("expected looksLikeSecret for %s to be %t, got %t", name, expected, got)
+		}
+	}
+}
+
+// Not parallel, as it captures the package's log output.
+func TestTypeValidateWarnsOnSecretOutput(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	for _, obj := range []*Type{
+		{Name: "accessToken", Type: "String", Output: true},
+		{Name: "sharedSecret", Type: "String", Output: true, Sensitive: true},
+	} {
+		obj.SetDefault(r)
+		obj.Validate(r.Name)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "access_token in resource test is an output field named like a secret") || strings.Contains(got, "shared_secret") {
+		t.Errorf("expected a warning for accessToken only, got:\n%s", got)
+	}
+}
+
+func TestTypeValidateAliases(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "unused alias",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kms_key"}},
+			fatal:       false,
+		},
+		{
+			description: "alias colliding with another field",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"display_name"}},
+			fatal:       true,
+		},
+		{
+			description: "alias colliding with the field itself",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kms_key_name"}},
+			fatal:       true,
+		},
+		{
+			description: "repeated alias",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kms_key", "kms_key"}},
+			fatal:       true,
+		},
+		{
+			description: "camelCase alias",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kmsKey"}},
+			fatal:       true,
+		},
+		{
+			description: "required field",
+			obj:         Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kms_key"}, Required: true},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			r.Properties = []*Type{&tc.obj, {Name: "displayName", Type: "String"}}
+			for _, p := range r.Properties {
+				p.SetDefault(r)
+			}
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeAliasProperties(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	key := &Type{Name: "kmsKeyName", Type: "String", Aliases: []string{"kms_key"}, DefaultValue: "default"}
+	key.SetDefault(r)
+	r.Properties = []*Type{key}
+
+	if got := r.AliasedProperties(); len(got) != 1 || got[0] != key {
+		t.Fatalf("expected kmsKeyName to be aliased, got %v", got)
+	}
+
+	contents := renderSchemaProperty(t, key)
+	for _, alias := range key.AliasProperties() {
+		contents += renderSchemaProperty(t, alias)
+	}
+	got := strings.Join(strings.Fields(contents), " ")
+
+	for _, expected := range []string{
+		`"kms_key_name": { Type: schema.TypeString, Optional: true,`,
+		`"kms_key": { Type: schema.TypeString, Computed: true, Optional: true, Deprecated: "` + "`kms_key`" + ` is deprecated`,
+		`ConflictsWith: []string{"kms_key_name"},`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected the schema to contain %q, got:\n%s", expected, contents)
+		}
+	}
+	if strings.Count(got, "Default:") != 1 {
+		t.Errorf("expected only the aliased field to have a default, got:\n%s", contents)
+	}
+	if len(key.Aliases) != 1 || key.DeprecationMessage != "" {
+		t.Errorf("expected the aliased field to be unchanged, got %v", key)
+	}
+
+	if got, expected := key.ConfigKey(), `tpgresource.AliasedKey(d, "kms_key_name", "kms_key")`; got != expected {
+		t.Errorf("expected the config key to be %s, got %s", expected, got)
+	}
+	if got, expected := key.AliasProperties()[0].ConfigKey(), `"kms_key"`; got != expected {
+		t.Errorf("expected the config key of the alias to be %s, got %s", expected, got)
+	}
+	if got := r.FieldAliases("kms_key_name"); !reflect.DeepEqual(got, []string{"kms_key"}) {
+		t.Errorf("expected the aliases of kms_key_name to be [kms_key], got %v", got)
+	}
+	if got := r.PropertyNamesToStrings(r.Properties); !reflect.DeepEqual(got, []string{"kms_key_name", "kms_key"}) {
+		t.Errorf("expected the names to include the alias, got %v", got)
+	}
+}
+
+func TestTypeSchemaType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+		objectSet   bool
+	}{
+		{
+			description: "array of objects",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "NestedObject"}},
+			expected:    "schema.TypeList",
+		},
+		{
+			description: "set of objects",
+			obj:         Type{Type: "Array", IsSet: true, ItemType: &Type{Type: "NestedObject"}},
+			expected:    "schema.TypeSet",
+			objectSet:   true,
+		},
+		{
+			description: "unordered list of objects",
+			obj:         Type{Type: "Array", UnorderedList: true, ItemType: &Type{Type: "NestedObject"}},
+			expected:    "schema.TypeSet",
+			objectSet:   true,
+		},
+		{
+			description: "set of strings",
+			obj:         Type{Type: "Array", IsSet: true, ItemType: &Type{Type: "String"}},
+			expected:    "schema.TypeSet",
+		},
+		{
+			description: "nested object",
+			obj:         Type{Type: "NestedObject"},
+			expected:    "schema.TypeList",
+		},
+		{
+			description: "nested object set",
+			obj:         Type{Type: "NestedObject", IsSet: true},
+			expected:    "schema.TypeSet",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.SchemaType(); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+			if got := tc.obj.IsObjectSet(); got != tc.objectSet {
+				t.Errorf("expected IsObjectSet to be %t, got %t", tc.objectSet, got)
+			}
+		})
+	}
+}
+
+func TestTypeUnorderedListIsSet(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	obj := &Type{Name: "ranges", Type: "Array", UnorderedList: true, ItemType: &Type{Type: "NestedObject", Properties: []*Type{{Name: "name", Type: "String"}}}}
+	obj.SetDefault(r)
+	obj.Validate(r.Name)
+
+	if !obj.IsSet {
+		t.Errorf("expected an unordered_list to be generated as a set")
+	}
+}
+
+func TestTypeValidateDefaultFromProvider(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "zone",
+			obj:         Type{Name: "zone", Type: "String", DefaultFromProvider: "zone"},
+			fatal:       false,
+		},
+		{
+			description: "unknown attribute",
+			obj:         Type{Name: "location", Type: "String", DefaultFromProvider: "location"},
+			fatal:       true,
+		},
+		{
+			description: "not a string",
+			obj:         Type{Name: "zone", Type: "Integer", DefaultFromProvider: "zone"},
+			fatal:       true,
+		},
+		{
+			description: "required",
+			obj:         Type{Name: "zone", Type: "String", DefaultFromProvider: "zone", Required: true},
+			fatal:       true,
+		},
+		{
+			description: "with default_from_api",
+			obj:         Type{Name: "zone", Type: "String", DefaultFromProvider: "zone", DefaultFromApi: true},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeValidateUniqueItems(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "array of strings",
+			obj:         Type{Name: "ports", Type: "Array", UniqueItems: true, ItemType: &Type{Type: "String"}},
+			fatal:       false,
+		},
+		{
+			description: "array of objects",
+			obj:         Type{Name: "rules", Type: "Array", UniqueItems: true, ItemType: &Type{Type: "NestedObject"}},
+			fatal:       true,
+		},
+		{
+			description: "string",
+			obj:         Type{Name: "port", Type: "String", UniqueItems: true},
+			fatal:       true,
+		},
+		{
+			description: "set",
+			obj:         Type{Name: "ports", Type: "Array", UniqueItems: true, IsSet: true, ItemType: &Type{Type: "String"}},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeFrameworkConstraintValidators(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	r.Properties = []*Type{
+		{Name: "network", Type: "String", Conflicts: []string{"network_config.0.subnetwork"}},
+		{
+			Name: "networkConfig",
+			Type: "NestedObject",
+			Properties: []*Type{
+				{Name: "subnetwork", Type: "String", Conflicts: []string{"network"}, RequiredWith: []string{"networkConfig.region"}},
+				{Name: "region", Type: "String"},
+				{Name: "mtu", Type: "Integer", AtLeastOneOf: []string{"network_config.0.mtu", "network_config.0.region"}},
+			},
+		},
+		{
+			Name:                "rules",
+			Type:                "Array",
+			ExactlyOneOfPerItem: true,
+			ItemType: &Type{
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "glob", Type: "String", ExactlyOneOf: []string{"glob", "regex"}},
+					{Name: "regex", Type: "String", ExactlyOneOf: []string{"glob", "regex"}},
+				},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+	config := r.Properties[1]
+	glob := r.Properties[2].ItemType.Properties[0]
+
+	cases := []struct {
+		description string
+		obj         *Type
+		expected    []string
+	}{
+		{
+			description: "top-level field conflicting with a nested field",
+			obj:         r.Properties[0],
+			expected:    []string{`stringvalidator.ConflictsWith(path.MatchRoot("network_config").AtListIndex(0).AtName("subnetwork"))`},
+		},
+		{
+			description: "nested field conflicting with a top-level field",
+			obj:         config.Properties[0],
+			expected: []string{
+				`stringvalidator.ConflictsWith(path.MatchRoot("network"))`,
+				`stringvalidator.AlsoRequires(path.MatchRoot("network_config").AtListIndex(0).AtName("region"))`,
+			},
+		},
+		{
+			description: "group including the field itself",
+			obj:         config.Properties[2],
+			expected:    []string{`int64validator.AtLeastOneOf(path.MatchRoot("network_config").AtListIndex(0).AtName("region"))`},
+		},
+		{
+			description: "group within the items of an array",
+			obj:         glob,
+			expected:    []string{`stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("regex"))`},
+		},
+		{
+			description: "no constraints",
+			obj:         config.Properties[1],
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.FrameworkConstraintValidators(); !slices.Equal(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTypeItemRangeValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		expected    string
+	}{
+		{
+			description: "no bounds",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "Integer"}},
+			expected:    "",
+		},
+		{
+			description: "integer bounds",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMinimum: "1", ItemMaximum: "65535"},
+			expected:    "validation.IntBetween(1, 65535)",
+		},
+		{
+			description: "integer minimum",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMinimum: "0"},
+			expected:    "validation.IntAtLeast(0)",
+		},
+		{
+			description: "double maximum",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "Double"}, ItemMaximum: "1.0"},
+			expected:    "validation.FloatAtMost(1.0)",
+		},
+		{
+			description: "double bounds",
+			obj:         Type{Type: "Array", ItemType: &Type{Type: "Double"}, ItemMinimum: "0", ItemMaximum: "1.0"},
+			expected:    "validation.FloatBetween(0, 1.0)",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := tc.obj.ItemRangeValidation(), tc.expected; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+	ports := &Type{Name: "ports", Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMinimum: "1", ItemMaximum: "65535"}
+	ports.SetDefault(r)
+
+	contents := renderSchemaProperty(t, ports)
+	if got := strings.Join(strings.Fields(contents), " "); !strings.Contains(got, "Elem: &schema.Schema{ Type: schema.TypeInt, ValidateFunc: validation.IntBetween(1, 65535), }") {
+		t.Errorf("expected the items to be validated, got:\n%s", contents)
+	}
+}
+
+func TestTypeValidateItemRange(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "test", ProductMetadata: &Product{Name: "test"}}
+
+	cases := []struct {
+		description string
+		obj         Type
+		fatal       bool
+	}{
+		{
+			description: "array of integers",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMinimum: "1", ItemMaximum: "10"},
+			fatal:       false,
+		},
+		{
+			description: "array of doubles",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "Double"}, ItemMaximum: "0.5"},
+			fatal:       false,
+		},
+		{
+			description: "array of strings",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "String"}, ItemMinimum: "1"},
+			fatal:       true,
+		},
+		{
+			description: "integer",
+			obj:         Type{Name: "test", Type: "Integer", ItemMinimum: "1"},
+			fatal:       true,
+		},
+		{
+			description: "with item_validation",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMinimum: "1", ItemValidation: resource.Validation{Function: "validation.IntAtLeast(1)"}},
+			fatal:       true,
+		},
+		{
+			description: "fractional bound on integers",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "Integer"}, ItemMaximum: "0.5"},
+			fatal:       true,
+		},
+		{
+			description: "item_minimum greater than item_maximum",
+			obj:         Type{Name: "test", Type: "Array", ItemType: &Type{Type: "Double"}, ItemMinimum: "2", ItemMaximum: "1"},
+			fatal:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.SetDefault(r)
+			if tc.fatal {
+				assertFatal(t, func() { tc.obj.Validate(r.Name) })
+			} else {
+				tc.obj.Validate(r.Name)
+			}
+		})
+	}
+}
+
+func TestTypeClientSide(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{Name: "topic"}
+	r.Properties = []*Type{
+		{Name: "name", Type: "String"},
+		{Name: "createTimeoutOverride", Type: "String", ClientSide: true, Immutable: true},
+		{Name: "policy", Type: "NestedObject", Properties: []*Type{
+			{Name: "regions", Type: "String"},
+			{Name: "localToggle", Type: "Boolean", ClientSide: true},
+		}},
+	}
+	r.SetDefault(&Product{Name: "test"})
+	override := r.Properties[1]
+	policy := r.Properties[2]
+
+	if !override.IsForceNew() {
+		t.Errorf("expected %s to force replacement", override.Name)
+	}
+	if r.IsSettableProperty(override) || slices.Contains(r.GettableProperties(), override) {
+		t.Errorf("expected %s not to be sent or read", override.Name)
+	}
+
+	got := renderPropertyMethods(t, policy, "expandPropertyMethod", "flattenPropertyMethod")
+	if strings.Contains(got, "PolicyLocalToggle") {
+		t.Errorf("expected no expand or flatten for the client-side field, got:\n%s", got)
+	}
+	if !strings.Contains(got, "PolicyRegions") {
+		t.Errorf("expected the expand and flatten for policy.regions, got:\n%s", got)
+	}
+	if !strings.Contains(got, `transformed["local_toggle"] = d.Get("policy.0.local_toggle")`) {
+		t.Errorf("expected the client-side field to keep its configured value, got:\n%s", got)
+	}
+
+	cases := []struct {
+		description string
+		obj         *Type
+	}{
+		{
+			description: "output",
+			obj:         &Type{Name: "local", Type: "String", ClientSide: true, Output: true},
+		},
+		{
+			description: "custom_expand",
+			obj:         &Type{Name: "local", Type: "String", ClientSide: true, CustomExpand: "templates/terraform/custom_expand/local.go.tmpl"},
+		},
+		{
+			description: "in an array",
+			obj: &Type{Name: "rules", Type: "Array", ItemType: &Type{Type: "NestedObject", Properties: []*Type{
+				{Name: "local", Type: "String", ClientSide: true},
+			}}},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Resource{Name: "topic", Properties: []*Type{tc.obj}}
+			r.SetDefault(&Product{Name: "test"})
+			assertFatal(t, func() {
+				for _, p := range r.AllNestedProperties(r.RootProperties()) {
+					p.validateClientSide(r.Name)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// In strict mode, Validate also runs the checks that are otherwise skipped,
+// and collects every failure instead of exiting on the first one, so that
+// they can all be fixed in one pass. Failures are reported by
+// ExitOnValidationFailures.
+var strictValidation = false
+
+var (
+	validationFailuresMu sync.Mutex
+	validationFailures   []string
+)
+
+// SetStrictValidation turns strict mode on or off, and discards any failures
+// collected so far.
+func SetStrictValidation(strict bool) {
+	validationFailuresMu.Lock()
+	defer validationFailuresMu.Unlock()
+
+	strictValidation = strict
+	validationFailures = nil
+}
+
+// Reports a failed check. Exits immediately, unless in strict mode, where the
+// failure is collected and validation continues.
+func validationFailure(format string, args ...interface{}) {
+	if !strictValidation {
+		log.Fatalf(format, args...)
+	}
+
+	validationFailuresMu.Lock()
+	defer validationFailuresMu.Unlock()
+
+	validationFailures = append(validationFailures, fmt.Sprintf(format, args...))
+}
+
+// ValidationFailures returns the failures collected in strict mode.
+func ValidationFailures() []string {
+	validationFailuresMu.Lock()
+	defer validationFailuresMu.Unlock()
+
+	return slices.Clone(validationFailures)
+}
+
+// ExitOnValidationFailures exits with a report of every failure collected in
+// strict mode, if there are any. Products are validated concurrently, so it's
+// called once all of them are done.
+func ExitOnValidationFailures() {
+	failures := ValidationFailures()
+	if len(failures) == 0 {
+		return
+	}
+
+	log.Fatalf("Validation failed with %d errors:\n%s", len(failures), strings.Join(failures, "\n"))
+}
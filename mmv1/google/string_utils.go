@@ -141,22 +141,26 @@ is transformed to
 	projects/(?P<project>[^/]+)/global/networks/(?P<name>[^/]+)
 
 Values marked with % are URL-encoded, and will match any number of /'s.
+Fields within a url_param_only nested object, written as {{object.field}},
+are captured as object__field, as group names can't contain dots.
 Note: ?P indicates a Python-compatible named capture group. Named groups
 aren't common in JS-based regex flavours, but are in Perl-based ones
 */
 func Format2Regex(format string) string {
-	re := regexp.MustCompile(`\{\{%([[:word:]]+)\}\}`)
+	re := regexp.MustCompile(`\{\{%([[:word:].]+)\}\}`)
 	result := re.ReplaceAllStringFunc(format, func(match string) string {
 		// TODO rewrite: the trims may not be needed with more effecient regex
 		word := strings.TrimPrefix(match, "{{")
 		word = strings.TrimSuffix(word, "}}")
 		word = strings.ReplaceAll(word, "%", "")
+		word = strings.ReplaceAll(word, ".", "__")
 		return fmt.Sprintf("(?P<%s>.+)", word)
 	})
-	re = regexp.MustCompile(`\{\{([[:word:]]+)\}\}`)
+	re = regexp.MustCompile(`\{\{([[:word:].]+)\}\}`)
 	result = re.ReplaceAllStringFunc(result, func(match string) string {
 		word := strings.TrimPrefix(match, "{{")
 		word = strings.TrimSuffix(word, "}}")
+		word = strings.ReplaceAll(word, ".", "__")
 		return fmt.Sprintf("(?P<%s>[^/]+)", word)
 	})
 	return result
@@ -158,3 +158,41 @@ func TestStringFirstSentence(t *testing.T) {
 		})
 	}
 }
+
+func TestStringFormat2Regex(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		format      string
+		expected    string
+	}{
+		{
+			description: "fields",
+			format:      "projects/{{project}}/global/networks/{{name}}",
+			expected:    "projects/(?P<project>[^/]+)/global/networks/(?P<name>[^/]+)",
+		},
+		{
+			description: "url-encoded field",
+			format:      "{{project}}/{{%name}}",
+			expected:    "(?P<project>[^/]+)/(?P<name>.+)",
+		},
+		{
+			description: "fields of an addressing object",
+			format:      "locations/{{target.location}}/targets/{{%target.name}}",
+			expected:    "locations/(?P<target__location>[^/]+)/targets/(?P<target__name>.+)",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Format2Regex(tc.format), tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
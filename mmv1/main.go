@@ -50,10 +50,20 @@ var yamlMode = flag.Bool("yaml", false, "copy text over from ruby yaml to go yam
 
 var showImportDiffs = flag.Bool("show-import-diffs", false, "write go import diffs to stdout")
 
+var strictValidation = flag.Bool("strict-validation", false, "run every validation check and report all failures together instead of exiting on the first")
+
+var maxPropertyDepth = flag.Int("max-property-depth", api.MaxPropertyDepth, "maximum nesting depth of properties before validation warns about them")
+
+var failOnMaxPropertyDepth = flag.Bool("fail-on-max-property-depth", api.FailOnMaxPropertyDepth, "fail validation, rather than warn, when properties are nested deeper than max-property-depth")
+
 func main() {
 
 	flag.Parse()
 
+	api.SetStrictValidation(*strictValidation)
+	api.MaxPropertyDepth = *maxPropertyDepth
+	api.FailOnMaxPropertyDepth = *failOnMaxPropertyDepth
+
 	if *openapiGenerate {
 		parser := openapi_generate.NewOpenapiParser("openapi_generate/openapi", "products")
 		parser.Run()
@@ -158,6 +168,7 @@ func main() {
 		go GenerateProduct(productFileChannel, providerToGenerate, productsForVersionChannel, startTime, productsToGenerate, *resourceToGenerate, *overrideDirectory, generateCode, generateDocs)
 	}
 	wg.Wait()
+	api.ExitOnValidationFailures()
 
 	close(productFileChannel)
 	close(productsForVersionChannel)
@@ -312,6 +323,13 @@ func GenerateProduct(productChannel chan string, providerToGenerate provider.Pro
 		return
 	}
 
+	// In strict mode, the failures of every product are reported together once
+	// they've all been validated, so nothing is generated after the first one.
+	if len(api.ValidationFailures()) > 0 {
+		log.Printf("%s: Validation failed, skipping generation", productName)
+		return
+	}
+
 	log.Printf("%s: Generating files", productName)
 	providerToGenerate.Generate(*outputPath, productName, resourceToGenerate, generateCode, generateDocs)
 }
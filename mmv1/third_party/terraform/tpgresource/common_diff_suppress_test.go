@@ -67,6 +67,89 @@ func TestDurationDiffSuppress(t *testing.T) {
 	}
 }
 
+func TestNumericDiffSuppress(t *testing.T) {
+	cases := map[string]struct {
+		Old, New           string
+		ExpectDiffSuppress bool
+	}{
+		"same values": {
+			Old:                "3",
+			New:                "3",
+			ExpectDiffSuppress: true,
+		},
+		"same values, different formats": {
+			Old:                "3",
+			New:                "3.0",
+			ExpectDiffSuppress: true,
+		},
+		"same values, different precision": {
+			Old:                "3.0",
+			New:                "3.00",
+			ExpectDiffSuppress: true,
+		},
+		"different values": {
+			Old:                "3",
+			New:                "3.5",
+			ExpectDiffSuppress: false,
+		},
+		"not a number": {
+			Old:                "",
+			New:                "3",
+			ExpectDiffSuppress: false,
+		},
+	}
+
+	for tn, tc := range cases {
+		if NumericDiffSuppress("key", tc.Old, tc.New, nil) != tc.ExpectDiffSuppress {
+			t.Fatalf("bad: %s, '%s' => '%s' expect %t", tn, tc.Old, tc.New, tc.ExpectDiffSuppress)
+		}
+	}
+}
+
+func TestQuantityDiffSuppress(t *testing.T) {
+	cases := map[string]struct {
+		Old, New           string
+		ExpectDiffSuppress bool
+	}{
+		"same values": {
+			Old:                "2Gi",
+			New:                "2Gi",
+			ExpectDiffSuppress: true,
+		},
+		"same size, different units": {
+			Old:                "2Gi",
+			New:                "2048Mi",
+			ExpectDiffSuppress: true,
+		},
+		"same size, bytes": {
+			Old:                "1Ki",
+			New:                "1024",
+			ExpectDiffSuppress: true,
+		},
+		"binary and decimal units": {
+			Old:                "1Gi",
+			New:                "1G",
+			ExpectDiffSuppress: false,
+		},
+		"different values": {
+			Old:                "2Gi",
+			New:                "3Gi",
+			ExpectDiffSuppress: false,
+		},
+		"invalid value": {
+			Old:                "2Gi",
+			New:                "2GB",
+			ExpectDiffSuppress: false,
+		},
+	}
+
+	for tn, tc := range cases {
+		if QuantityDiffSuppress("key", tc.Old, tc.New, nil) != tc.ExpectDiffSuppress {
+			t.Fatalf("bad: %s, '%s' => '%s' expect %t", tn, tc.Old, tc.New, tc.ExpectDiffSuppress)
+		}
+	}
+}
+
 func TestEmptyOrUnsetBlockDiffSuppress(t *testing.T) {
 	cases := map[string]struct {
 		Key, Old, New      string
@@ -28,38 +28,37 @@ func ParseImportId(idRegexes []string, d TerraformResourceData, config *transpor
 
 		if fieldValues := re.FindStringSubmatch(d.Id()); fieldValues != nil {
 			log.Printf("[DEBUG] matching ID %s to regex %s.", d.Id(), idFormat)
+			// Fields within url_param_only nested objects are set together,
+			// once per object.
+			blocks := make(map[string]map[string]interface{})
 			// Starting at index 1, the first match is the full string.
 			for i := 1; i < len(fieldValues); i++ {
 				fieldName := re.SubexpNames()[i]
 				fieldValue := fieldValues[i]
 				log.Printf("[DEBUG] importing %s = %s", fieldName, fieldValue)
-				// Because we do not know at this point whether 'fieldName'
-				// corresponds to a TypeString or a TypeInteger in the resource
-				// schema, we need to determine the type in an unintuitive way.
-				// We call d.Get, because examining the empty value is the easiest
-				// way to get that out.  Normally, we would be able to just
-				// use a try/catch pattern - try as a string, and if that doesn't
-				// work, try as an integer, and if that doesn't work, return the
-				// error.  Unfortunately, this is not possible here - during tests,
-				// d.Set(...) will panic if there is an error.
-				val, _ := d.GetOk(fieldName)
-				if _, ok := val.(string); val == nil || ok {
-					if err = d.Set(fieldName, fieldValue); err != nil {
+				if block, child, ok := strings.Cut(fieldName, AddressingFieldSeparator); ok {
+					val, err := importFieldValue(d, AddressingFieldPath(block+"."+child), fieldValue)
+					if err != nil {
 						return err
 					}
-				} else if _, ok := val.(int); ok {
-					if intVal, atoiErr := strconv.Atoi(fieldValue); atoiErr == nil {
-						// If the value can be parsed as an integer, we try to set the
-						// value as an integer.
-						if err = d.Set(fieldName, intVal); err != nil {
-							return err
-						}
-					} else {
-						return fmt.Errorf("%s appears to be an integer, but %v cannot be parsed as an int", fieldName, fieldValue)
+					if blocks[block] == nil {
+						blocks[block] = make(map[string]interface{})
 					}
-				} else {
-					return fmt.Errorf(
-						"cannot handle %s, which currently has value %v, and should be set to %#v, during import", fieldName, val, fieldValue)
+					blocks[block][child] = val
+					continue
+				}
+
+				val, err := importFieldValue(d, fieldName, fieldValue)
+				if err != nil {
+					return err
+				}
+				if err = d.Set(fieldName, val); err != nil {
+					return err
+				}
+			}
+			for block, values := range blocks {
+				if err := d.Set(block, []interface{}{values}); err != nil {
+					return err
 				}
 			}
 
@@ -75,6 +74,46 @@ func ParseImportId(idRegexes []string, d TerraformResourceData, config *transpor
 	return fmt.Errorf("Import id %q doesn't match any of the accepted formats: %v", d.Id(), idRegexes)
 }
 
+// Separates the object and field names of a field within a url_param_only
+// nested object in the capture group names of import ID regexes, which can't
+// contain dots. eg. {{target.name}} is captured as (?P<target__name>[^/]+).
+const AddressingFieldSeparator = "__"
+
+// Returns the schema path of a field referenced as {{object.field}} in a URL,
+// for fields within a url_param_only nested object. eg. "target.name" becomes
+// "target.0.name". Other names are returned as is.
+func AddressingFieldPath(name string) string {
+	return strings.Replace(name, ".", ".0.", 1)
+}
+
+// Converts a value captured from an import ID to the type of the field at the
+// given path.
+func importFieldValue(d TerraformResourceData, path, fieldValue string) (interface{}, error) {
+	// Because we do not know at this point whether 'path' corresponds to a
+	// TypeString or a TypeInteger in the resource schema, we need to determine
+	// the type in an unintuitive way. We call d.Get, because examining the
+	// empty value is the easiest way to get that out.  Normally, we would be
+	// able to just use a try/catch pattern - try as a string, and if that
+	// doesn't work, try as an integer, and if that doesn't work, return the
+	// error.  Unfortunately, this is not possible here - during tests,
+	// d.Set(...) will panic if there is an error.
+	val, _ := d.GetOk(path)
+	if _, ok := val.(string); val == nil || ok {
+		return fieldValue, nil
+	}
+	if _, ok := val.(int); ok {
+		// If the value can be parsed as an integer, we try to set the value as
+		// an integer.
+		intVal, err := strconv.Atoi(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s appears to be an integer, but %v cannot be parsed as an int", path, fieldValue)
+		}
+		return intVal, nil
+	}
+	return nil, fmt.Errorf(
+		"cannot handle %s, which currently has value %v, and should be set to %#v, during import", path, val, fieldValue)
+}
+
 func setDefaultValues(idRegex string, d TerraformResourceData, config *transport_tpg.Config) error {
 	if _, ok := d.GetOk("project"); !ok && strings.Contains(idRegex, "?P<project>") {
 		project, err := GetProject(d, config)
@@ -1,6 +1,7 @@
 package tpgresource
 
 import (
+	"reflect"
 	"testing"
 
 	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
@@ -110,6 +111,21 @@ func TestParseImportId(t *testing.T) {
 				"name":    "my-subnetwork",
 			},
 		},
+		"url_param_only nested object": {
+			ImportId: "projects/my-project/locations/my-location/targets/my-target",
+			IdRegexes: []string{
+				"projects/(?P<target__project>[^/]+)/locations/(?P<target__location>[^/]+)/targets/(?P<target__name>[^/]+)",
+			},
+			ExpectedSchemaValues: map[string]interface{}{
+				"target": []interface{}{
+					map[string]interface{}{
+						"project":  "my-project",
+						"location": "my-location",
+						"name":     "my-target",
+					},
+				},
+			},
+		},
 		"invalid import id": {
 			ImportId:    "i/n/v/a/l/i/d",
 			IdRegexes:   regionalIdRegexes,
@@ -135,7 +151,7 @@ func TestParseImportId(t *testing.T) {
 		if err := ParseImportId(tc.IdRegexes, d, config); err == nil {
 			for k, expectedValue := range tc.ExpectedSchemaValues {
 				if v, ok := d.GetOk(k); ok {
-					if v != expectedValue {
+					if !reflect.DeepEqual(v, expectedValue) {
 						t.Errorf("%s failed; Expected value %q for field %q, got %q", tn, expectedValue, k, v)
 					}
 				} else {
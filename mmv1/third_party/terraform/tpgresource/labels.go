@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -233,6 +234,122 @@ func SetMetadataLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta inter
 	return nil
 }
 
+// The CustomizeDiff func to set the values of terraform_labels and effective_labels fields
+// when labels field is inside a single nested block other than "metadata". blockPath is the
+// terraform lineage of the block, e.g. "spec" or "spec.0.template".
+func SetNestedLabelsDiff(blockPath string) func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		return setNestedLabelsFields(blockPath, d, meta)
+	}
+}
+
+// Sets the values of terraform_labels and effective_labels fields when labels field is inside
+// a single nested block. As SetNew doesn't work on nested fields in terraform sdk, the whole
+// top level block is set with the updated values.
+// https://github.com/hashicorp/terraform-plugin-sdk/issues/459
+func setNestedLabelsFields(blockPath string, d *schema.ResourceDiff, meta interface{}) error {
+	parts := strings.Split(blockPath, ".")
+	root := parts[0]
+
+	l, ok := d.Get(root).([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	// Walk the plan down to the block, and skip the diff while the labels are unknown.
+	plan := d.GetRawPlan().GetAttr(root)
+	for _, part := range append(parts[1:], "labels") {
+		if plan.IsNull() || !plan.IsKnown() {
+			return nil
+		}
+		if index, err := strconv.Atoi(part); err == nil {
+			values := plan.AsValueSlice()
+			if len(values) <= index {
+				return nil
+			}
+			plan = values[index]
+		} else {
+			plan = plan.GetAttr(part)
+		}
+	}
+	if !plan.IsWhollyKnown() {
+		return nil
+	}
+
+	raw := d.Get(blockPath + ".labels")
+	if raw == nil {
+		return nil
+	}
+
+	if d.Get(blockPath+".terraform_labels") == nil {
+		return fmt.Errorf("`%s.terraform_labels` field is not present in the resource schema.", blockPath)
+	}
+
+	if d.Get(blockPath+".effective_labels") == nil {
+		return fmt.Errorf("`%s.effective_labels` field is not present in the resource schema.", blockPath)
+	}
+
+	// Find the block within the top level value, which is then modified in place.
+	block := l[0].(map[string]interface{})
+	for i := 2; i < len(parts); i += 2 {
+		nested, ok := block[parts[i]].([]interface{})
+		if !ok || len(nested) == 0 || nested[0] == nil {
+			return nil
+		}
+		block = nested[0].(map[string]interface{})
+	}
+
+	config := meta.(*transport_tpg.Config)
+
+	// Merge provider default labels with the user defined labels in the resource to get terraform managed labels
+	terraformLabels := make(map[string]string)
+	for k, v := range config.DefaultLabels {
+		terraformLabels[k] = v
+	}
+
+	// Append optional label indicating the resource was provisioned using Terraform
+	if config.AddTerraformAttributionLabel {
+		if el, ok := d.Get(blockPath + ".effective_labels").(map[string]any); ok {
+			_, hasExistingLabel := el[transport_tpg.AttributionKey]
+			if hasExistingLabel ||
+				config.TerraformAttributionLabelAdditionStrategy == transport_tpg.ProactiveAttributionStrategy ||
+				(config.TerraformAttributionLabelAdditionStrategy == transport_tpg.CreateOnlyAttributionStrategy && d.Id() == "") {
+				terraformLabels[transport_tpg.AttributionKey] = transport_tpg.AttributionValue
+			}
+		}
+	}
+
+	labels := raw.(map[string]interface{})
+	for k, v := range labels {
+		terraformLabels[k] = v.(string)
+	}
+
+	block["terraform_labels"] = terraformLabels
+	if err := d.SetNew(root, l); err != nil {
+		return fmt.Errorf("error setting new %s diff: %w", root, err)
+	}
+
+	o, n := d.GetChange(blockPath + ".terraform_labels")
+	effectiveLabels := d.Get(blockPath + ".effective_labels").(map[string]interface{})
+
+	for k, v := range n.(map[string]interface{}) {
+		effectiveLabels[k] = v.(string)
+	}
+
+	for k := range o.(map[string]interface{}) {
+		if _, ok := n.(map[string]interface{})[k]; !ok {
+			delete(effectiveLabels, k)
+		}
+	}
+
+	block["effective_labels"] = effectiveLabels
+	if err := d.SetNew(root, l); err != nil {
+		return fmt.Errorf("error setting new %s diff: %w", root, err)
+	}
+
+	return nil
+}
+
 // Upgrade the field "labels" in the state to exclude the labels with the labels prefix
 // and the field "effective_labels" to have all of labels, including the labels with the labels prefix
 func LabelsStateUpgrade(rawState map[string]interface{}, labesPrefix string) (map[string]interface{}, error) {
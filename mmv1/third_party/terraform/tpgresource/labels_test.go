@@ -0,0 +1,117 @@
+package tpgresource_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func nestedLabelsResource(computed bool) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: computed,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"labels": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"terraform_labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"effective_labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: tpgresource.SetNestedLabelsDiff("settings.0"),
+	}
+}
+
+func TestSetNestedLabelsDiff(t *testing.T) {
+	labels := map[string]interface{}{"env": "test"}
+	state := &terraform.InstanceState{
+		ID: "test",
+		Attributes: map[string]string{
+			"id":                                 "test",
+			"settings.#":                         "1",
+			"settings.0.labels.%":                "0",
+			"settings.0.terraform_labels.%":      "0",
+			"settings.0.effective_labels.%":      "1",
+			"settings.0.effective_labels.system": "true",
+		},
+		RawPlan: cty.ObjectVal(map[string]cty.Value{
+			"settings": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"labels": cty.MapVal(map[string]cty.Value{"env": cty.StringVal("test")}),
+				}),
+			}),
+		}),
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"settings": []interface{}{map[string]interface{}{"labels": labels}},
+	})
+	meta := &transport_tpg.Config{DefaultLabels: map[string]string{"team": "network"}}
+
+	cases := map[string]struct {
+		computed      bool
+		expectedError string
+	}{
+		"computed block": {
+			computed: true,
+		},
+		"block that isn't computed": {
+			computed:      false,
+			expectedError: "settings",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			diff, err := nestedLabelsResource(tc.computed).Diff(context.Background(), state, config, meta)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected an error setting %s, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string]string)
+			for k, attr := range diff.Attributes {
+				got[k] = attr.New
+			}
+			expected := map[string]string{
+				"settings.0.terraform_labels.env":  "test",
+				"settings.0.terraform_labels.team": "network",
+				"settings.0.effective_labels.env":  "test",
+				"settings.0.effective_labels.team": "network",
+			}
+			for k, v := range expected {
+				if got[k] != v {
+					t.Errorf("expected %s to be %q in the diff, got %q", k, v, got[k])
+				}
+			}
+		})
+	}
+}
@@ -150,6 +150,28 @@ func GetZoneFromDiff(d *schema.ResourceDiff, config *transport_tpg.Config) (stri
 	return "", fmt.Errorf("%s: required field is not set", "zone")
 }
 
+// GetProviderDefault returns the value of the provider-level attribute, one of
+// "project", "region" or "zone", used as the default of fields that are left
+// empty. If the provider's value is not given, an error is returned.
+func GetProviderDefault(attribute string, config *transport_tpg.Config) (string, error) {
+	var value string
+	switch attribute {
+	case "project":
+		value = config.Project
+	case "region":
+		value = config.Region
+	case "zone":
+		value = config.Zone
+	default:
+		return "", fmt.Errorf("%s is not a provider-level default", attribute)
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("Cannot determine %s: set in this resource, or set provider-level %s.", attribute, attribute)
+	}
+	return GetResourceNameFromSelfLink(value), nil
+}
+
 func GetRouterLockName(region string, router string) string {
 	return fmt.Sprintf("router/%s/%s", region, router)
 }
@@ -583,6 +605,34 @@ func ExpandString(v interface{}, d TerraformResourceData, config *transport_tpg.
 	return v.(string), nil
 }
 
+// ExpandNullable returns a pointer to the value of a field that is set in the
+// configuration, or nil if it isn't, so that an explicit zero value (such as
+// false) is sent to the API while an unset field is omitted.
+func ExpandNullable[T any](v interface{}, d TerraformResourceData, key string) (*T, error) {
+	if _, ok := d.GetOkExists(key); !ok {
+		return nil, nil
+	}
+
+	val, ok := v.(T)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for %s", v, key)
+	}
+	return &val, nil
+}
+
+// ValueAtPath follows a dotted path of keys into nested objects in an API
+// response, returning nil if any part of the path is missing.
+func ValueAtPath(v interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+	return v
+}
+
 func ChangeFieldSchemaToForceNew(sch *schema.Schema) {
 	sch.ForceNew = true
 	switch sch.Type {
@@ -685,6 +735,32 @@ func IsEmptyValue(v reflect.Value) bool {
 	return false
 }
 
+// IsEffectivelyEmpty returns whether an expanded value is nil, a zero value,
+// or a list or object whose entries are all effectively empty themselves. It
+// doesn't know which objects allow_empty_object keeps, so it's only generated
+// for fields that can't contain one.
+func IsEffectivelyEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, val := range v {
+			if !IsEffectivelyEmpty(val) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		for _, val := range v {
+			if !IsEffectivelyEmpty(val) {
+				return false
+			}
+		}
+		return true
+	}
+	return IsEmptyValue(reflect.ValueOf(v))
+}
+
 func ReplaceVars(d TerraformResourceData, config *transport_tpg.Config, linkTmpl string) (string, error) {
 	return ReplaceVarsRecursive(d, config, linkTmpl, false, 0)
 }
@@ -710,7 +786,7 @@ func ReplaceVarsRecursive(d TerraformResourceData, config *transport_tpg.Config,
 	}
 
 	// https://github.com/google/re2/wiki/Syntax
-	re := regexp.MustCompile("{{([%[:word:]]+)}}")
+	re := regexp.MustCompile("{{([%[:word:].]+)}}")
 	f, err := BuildReplacementFunc(re, d, config, linkTmpl, shorten)
 	if err != nil {
 		return "", err
@@ -797,12 +873,12 @@ func BuildReplacementFunc(re *regexp.Regexp, d TerraformResourceData, config *tr
 			return zone
 		}
 		if string(m[0]) == "%" {
-			v, ok := d.GetOkExists(m[1:])
+			v, ok := d.GetOkExists(AddressingFieldPath(m[1:]))
 			if ok {
 				return url.PathEscape(fmt.Sprintf("%v", v))
 			}
 		} else {
-			v, ok := d.GetOkExists(m)
+			v, ok := d.GetOkExists(AddressingFieldPath(m))
 			if ok {
 				if shorten {
 					return GetResourceNameFromSelfLink(fmt.Sprintf("%v", v))
@@ -842,6 +918,221 @@ func GetContentMd5Hash(content []byte) string {
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
+// ForceNewIfSet returns a CustomizeDiffFunc that forces replacement when the
+// field at key changes from a previously set value, while still allowing an
+// unset field to be set in place.
+func ForceNewIfSet(key string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return ForceNewIfSetLogic(key, diff)
+	}
+}
+
+func ForceNewIfSetLogic(key string, diff TerraformResourceDiff) error {
+	if !diff.HasChange(key) {
+		return nil
+	}
+
+	old, _ := diff.GetChange(key)
+	if IsEmptyValue(reflect.ValueOf(old)) {
+		return nil
+	}
+
+	return diff.ForceNew(key)
+}
+
+// DefaultFromField returns a CustomizeDiffFunc that sets the field at key to
+// the value of the field at from when it's empty.
+func DefaultFromField(key, from string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return DefaultFromFieldLogic(key, from, diff)
+	}
+}
+
+func DefaultFromFieldLogic(key, from string, diff TerraformResourceDiff) error {
+	if !IsEmptyValue(reflect.ValueOf(diff.Get(key))) {
+		return nil
+	}
+
+	value := diff.Get(from)
+	if IsEmptyValue(reflect.ValueOf(value)) {
+		return nil
+	}
+
+	return diff.SetNew(key, value)
+}
+
+// AliasField returns a CustomizeDiffFunc that copies the value of the field at
+// key into alias, a deprecated former name of the field, when the field is set
+// or changed in the configuration. The alias must be computed, as only computed
+// keys can be set from a CustomizeDiff.
+func AliasField(key, alias string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return AliasFieldLogic(key, alias, diff)
+	}
+}
+
+func AliasFieldLogic(key, alias string, diff TerraformResourceDiff) error {
+	if !diff.HasChange(key) {
+		return nil
+	}
+
+	value := diff.Get(key)
+	if IsEmptyValue(reflect.ValueOf(value)) {
+		return nil
+	}
+
+	return diff.SetNew(alias, value)
+}
+
+// AliasedKey returns the key that the value of the field at key should be read
+// from: the first of its aliases that is set in the configuration when the
+// field itself isn't, and key otherwise.
+func AliasedKey(d *schema.ResourceData, key string, aliases ...string) string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.GetAttr(key).IsNull() {
+		return key
+	}
+	for _, alias := range aliases {
+		if !config.GetAttr(alias).IsNull() {
+			return alias
+		}
+	}
+	return key
+}
+
+// SetAliasedField sets the field at key and its aliases to value. When the
+// field is empty in the state but one of its aliases isn't, the configuration
+// uses the alias, so the field is left empty to match it.
+func SetAliasedField(d TerraformResourceData, key string, value interface{}, aliases ...string) error {
+	keys := append([]string{key}, aliases...)
+	if IsEmptyValue(reflect.ValueOf(d.Get(key))) {
+		for _, alias := range aliases {
+			if !IsEmptyValue(reflect.ValueOf(d.Get(alias))) {
+				keys = aliases
+				break
+			}
+		}
+	}
+	for _, k := range keys {
+		if err := d.Set(k, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResourceDiffSuppressFunc is a DiffSuppressFunc that is given the whole
+// diff of the resource, so that it can depend on the state of other fields.
+type ResourceDiffSuppressFunc func(k, old, new string, diff TerraformResourceDiff) bool
+
+// SuppressDiffWithResourceDiff returns a CustomizeDiffFunc that clears the diff
+// of the field at key when suppress returns true for it. The field must be
+// computed, as only computed keys can be cleared.
+func SuppressDiffWithResourceDiff(key string, suppress ResourceDiffSuppressFunc) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return SuppressDiffWithResourceDiffLogic(key, suppress, diff)
+	}
+}
+
+func SuppressDiffWithResourceDiffLogic(key string, suppress ResourceDiffSuppressFunc, diff TerraformResourceDiff) error {
+	if !diff.HasChange(key) {
+		return nil
+	}
+
+	old, new := diff.GetChange(key)
+	if !suppress(key, fmt.Sprint(old), fmt.Sprint(new), diff) {
+		return nil
+	}
+
+	return diff.Clear(key)
+}
+
+// ExactlyOneOfPerItem checks that exactly one of the given fields is set within
+// each element of the list at listPath, which the schema's ExactlyOneOf can't
+// express. "*" in listPath matches every index of an enclosing list, eg.
+// "rules.*.matches".
+func ExactlyOneOfPerItem(listPath string, fields []string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return ExactlyOneOfPerItemLogic(listPath, fields, diff)
+	}
+}
+
+func ExactlyOneOfPerItemLogic(listPath string, fields []string, diff TerraformResourceDiff) error {
+	// values that aren't known until apply can't be checked
+	known, checkKnown := diff.(interface{ NewValueKnown(string) bool })
+
+	for _, path := range expandListPaths(listPath, diff) {
+		items, _ := diff.Get(path).([]interface{})
+		for i := range items {
+			set := 0
+			unknown := false
+			for _, field := range fields {
+				key := fmt.Sprintf("%s.%d.%s", path, i, field)
+				if checkKnown && !known.NewValueKnown(key) {
+					unknown = true
+					break
+				}
+				if !IsEmptyValue(reflect.ValueOf(diff.Get(key))) {
+					set++
+				}
+			}
+			if !unknown && set != 1 {
+				return fmt.Errorf("%s.%d: exactly one of `%s` must be specified", path, i, strings.Join(fields, ","))
+			}
+		}
+	}
+	return nil
+}
+
+// UniqueItems checks that the list at listPath doesn't contain the same item
+// twice, for ordered lists that don't get uniqueness from a set. "*" in
+// listPath matches every index of an enclosing list, eg. "rules.*.ports".
+func UniqueItems(listPath string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+		return UniqueItemsLogic(listPath, diff)
+	}
+}
+
+func UniqueItemsLogic(listPath string, diff TerraformResourceDiff) error {
+	// values that aren't known until apply can't be checked
+	known, checkKnown := diff.(interface{ NewValueKnown(string) bool })
+
+	for _, path := range expandListPaths(listPath, diff) {
+		if checkKnown && !known.NewValueKnown(path) {
+			continue
+		}
+
+		items, _ := diff.Get(path).([]interface{})
+		seen := make(map[interface{}]int, len(items))
+		for i, item := range items {
+			if checkKnown && !known.NewValueKnown(fmt.Sprintf("%s.%d", path, i)) {
+				continue
+			}
+			if j, ok := seen[item]; ok {
+				return fmt.Errorf("%s.%d: duplicate of item %d, %v; items must be unique", path, i, j, item)
+			}
+			seen[item] = i
+		}
+	}
+	return nil
+}
+
+// Returns the concrete paths matched by a list path containing "*" in place
+// of list indices.
+func expandListPaths(listPath string, diff TerraformResourceDiff) []string {
+	before, after, found := strings.Cut(listPath, ".*.")
+	if !found {
+		return []string{listPath}
+	}
+
+	var paths []string
+	items, _ := diff.Get(before).([]interface{})
+	for i := range items {
+		paths = append(paths, expandListPaths(fmt.Sprintf("%s.%d.%s", before, i, after), diff)...)
+	}
+	return paths
+}
+
 func DefaultProviderProject(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
 
 	config := meta.(*transport_tpg.Config)
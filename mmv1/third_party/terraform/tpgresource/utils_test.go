@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
@@ -283,6 +285,45 @@ func TestGetProject(t *testing.T) {
 	}
 }
 
+func TestExpandNullable(t *testing.T) {
+	nullableSchema := map[string]*schema.Schema{
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+
+	cases := map[string]struct {
+		ResourceConfig map[string]interface{}
+		Expected       *bool
+	}{
+		"unset value is nil": {
+			ResourceConfig: map[string]interface{}{},
+			Expected:       nil,
+		},
+		"explicit false is kept": {
+			ResourceConfig: map[string]interface{}{
+				"enabled": false,
+			},
+			Expected: new(bool),
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			d := tpgresource.SetupTestResourceDataFromConfigMap(t, nullableSchema, tc.ResourceConfig)
+
+			v, err := tpgresource.ExpandNullable[bool](d.Get("enabled"), d, "enabled")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(v, tc.Expected) {
+				t.Fatalf("Incorrect value: got %v, want %v", v, tc.Expected)
+			}
+		})
+	}
+}
+
 func TestGetLocation(t *testing.T) {
 	cases := map[string]struct {
 		ResourceConfig   map[string]interface{}
@@ -1125,6 +1166,15 @@ func TestReplaceVars(t *testing.T) {
 			},
 			Expected: "projects/project1/zones/wrapperinnerwrapper/instances/instance1",
 		},
+		"url_param_only nested object values": {
+			Template: "projects/{{target.project}}/locations/{{target.location}}/targets/{{%target.name}}",
+			SchemaValues: map[string]interface{}{
+				"target.0.project":  "project1",
+				"target.0.location": "location1",
+				"target.0.name":     "target/1",
+			},
+			Expected: "projects/project1/locations/location1/targets/target%2F1",
+		},
 		"base path recursive replacement": {
 			Template: "{{CloudRunBasePath}}namespaces/{{project}}/services",
 			Config: &transport_tpg.Config{
@@ -1166,3 +1216,501 @@ func TestReplaceVars(t *testing.T) {
 		})
 	}
 }
+
+func TestValueAtPath(t *testing.T) {
+	cases := map[string]struct {
+		Value    interface{}
+		Path     string
+		Expected interface{}
+	}{
+		"single key": {
+			Value:    map[string]interface{}{"value": "x"},
+			Path:     "value",
+			Expected: "x",
+		},
+		"nested keys": {
+			Value:    map[string]interface{}{"wrapper": map[string]interface{}{"value": "x"}},
+			Path:     "wrapper.value",
+			Expected: "x",
+		},
+		"missing key": {
+			Value:    map[string]interface{}{"other": "x"},
+			Path:     "value",
+			Expected: nil,
+		},
+		"not an object": {
+			Value:    "x",
+			Path:     "value",
+			Expected: nil,
+		},
+		"nil value": {
+			Value:    nil,
+			Path:     "value",
+			Expected: nil,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := tpgresource.ValueAtPath(tc.Value, tc.Path); !reflect.DeepEqual(got, tc.Expected) {
+			t.Errorf("bad: %s, got %#v, want %#v", tn, got, tc.Expected)
+		}
+	}
+}
+
+func TestIsEffectivelyEmpty(t *testing.T) {
+	cases := map[string]struct {
+		Value    interface{}
+		Expected bool
+	}{
+		"nil": {
+			Value:    nil,
+			Expected: true,
+		},
+		"zero value": {
+			Value:    "",
+			Expected: true,
+		},
+		"nested empty objects": {
+			Value:    map[string]interface{}{"inner": map[string]interface{}{"size": 0}, "items": []interface{}{map[string]interface{}{}}},
+			Expected: true,
+		},
+		"nested value": {
+			Value:    map[string]interface{}{"inner": map[string]interface{}{"size": 1}},
+			Expected: false,
+		},
+		"list of strings": {
+			Value:    []string{"a"},
+			Expected: false,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := tpgresource.IsEffectivelyEmpty(tc.Value); got != tc.Expected {
+			t.Errorf("bad: %s, got %t, want %t", tn, got, tc.Expected)
+		}
+	}
+}
+
+func TestForceNewIfSetLogic(t *testing.T) {
+	cases := map[string]struct {
+		Before, After  map[string]interface{}
+		ExpectForceNew bool
+	}{
+		"no change": {
+			Before:         map[string]interface{}{"field": "a"},
+			After:          map[string]interface{}{"field": "a"},
+			ExpectForceNew: false,
+		},
+		"set a previously unset value": {
+			Before:         map[string]interface{}{"field": ""},
+			After:          map[string]interface{}{"field": "a"},
+			ExpectForceNew: false,
+		},
+		"change a previously set value": {
+			Before:         map[string]interface{}{"field": "a"},
+			After:          map[string]interface{}{"field": "b"},
+			ExpectForceNew: true,
+		},
+		"unset a previously set value": {
+			Before:         map[string]interface{}{"field": "a"},
+			After:          map[string]interface{}{"field": ""},
+			ExpectForceNew: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			Before: tc.Before,
+			After:  tc.After,
+		}
+		if err := tpgresource.ForceNewIfSetLogic("field", d); err != nil {
+			t.Fatalf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if d.IsForceNew != tc.ExpectForceNew {
+			t.Errorf("bad: %s, expected force new to be %t", tn, tc.ExpectForceNew)
+		}
+	}
+}
+
+func TestDefaultFromFieldLogic(t *testing.T) {
+	cases := map[string]struct {
+		After    map[string]interface{}
+		Expected interface{}
+	}{
+		"empty field takes the value of the other field": {
+			After:    map[string]interface{}{"display_name": "", "name": "foo"},
+			Expected: "foo",
+		},
+		"set field is kept": {
+			After:    map[string]interface{}{"display_name": "bar", "name": "foo"},
+			Expected: "bar",
+		},
+		"both fields empty": {
+			After:    map[string]interface{}{"display_name": "", "name": ""},
+			Expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			Before: map[string]interface{}{},
+			After:  tc.After,
+			Schema: map[string]*schema.Schema{
+				"display_name": {Type: schema.TypeString, Optional: true, Computed: true},
+			},
+		}
+		if err := tpgresource.DefaultFromFieldLogic("display_name", "name", d); err != nil {
+			t.Fatalf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if got := d.Get("display_name"); got != tc.Expected {
+			t.Errorf("bad: %s, expected display_name to be %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestExactlyOneOfPerItemLogic(t *testing.T) {
+	rules := []interface{}{map[string]interface{}{}, map[string]interface{}{}}
+	cases := map[string]struct {
+		ListPath    string
+		After       map[string]interface{}
+		ExpectError bool
+	}{
+		"one field set in each element": {
+			ListPath: "rules",
+			After: map[string]interface{}{
+				"rules":         rules,
+				"rules.0.glob":  "*.html",
+				"rules.1.regex": ".*",
+			},
+		},
+		"no field set in an element": {
+			ListPath: "rules",
+			After: map[string]interface{}{
+				"rules":        rules,
+				"rules.0.glob": "*.html",
+			},
+			ExpectError: true,
+		},
+		"both fields set in an element": {
+			ListPath: "rules",
+			After: map[string]interface{}{
+				"rules":         rules,
+				"rules.0.glob":  "*.html",
+				"rules.1.glob":  "*.js",
+				"rules.1.regex": ".*",
+			},
+			ExpectError: true,
+		},
+		"elements of a nested list": {
+			ListPath: "rules.*.matches",
+			After: map[string]interface{}{
+				"rules":                  rules,
+				"rules.0.matches":        []interface{}{map[string]interface{}{}},
+				"rules.0.matches.0.glob": "*.html",
+				"rules.1.matches":        []interface{}{map[string]interface{}{}},
+			},
+			ExpectError: true,
+		},
+		"empty list": {
+			ListPath: "rules",
+			After:    map[string]interface{}{},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			Before: map[string]interface{}{},
+			After:  tc.After,
+		}
+		err := tpgresource.ExactlyOneOfPerItemLogic(tc.ListPath, []string{"glob", "regex"}, d)
+		if err != nil && !tc.ExpectError {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if err == nil && tc.ExpectError {
+			t.Errorf("bad: %s, expected an error", tn)
+		}
+	}
+}
+
+func TestSuppressDiffWithResourceDiffLogic(t *testing.T) {
+	// suppresses changes to "size" while "auto_resize" is enabled
+	suppress := func(_, _, _ string, d tpgresource.TerraformResourceDiff) bool {
+		return d.Get("auto_resize") == true
+	}
+
+	cases := map[string]struct {
+		Before        map[string]interface{}
+		After         map[string]interface{}
+		ExpectCleared bool
+	}{
+		"suppressed by another field": {
+			Before:        map[string]interface{}{"size": 10},
+			After:         map[string]interface{}{"size": 20, "auto_resize": true},
+			ExpectCleared: true,
+		},
+		"not suppressed": {
+			Before: map[string]interface{}{"size": 10},
+			After:  map[string]interface{}{"size": 20, "auto_resize": false},
+		},
+		"no change": {
+			Before: map[string]interface{}{"size": 10},
+			After:  map[string]interface{}{"size": 10, "auto_resize": true},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			Before: tc.Before,
+			After:  tc.After,
+		}
+		if err := tpgresource.SuppressDiffWithResourceDiffLogic("size", suppress, d); err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if cleared := d.Cleared["size"] != nil; cleared != tc.ExpectCleared {
+			t.Errorf("bad: %s, expected cleared to be %t, got %t", tn, tc.ExpectCleared, cleared)
+		}
+	}
+}
+
+func TestAliasFieldLogic(t *testing.T) {
+	cases := map[string]struct {
+		Before   map[string]interface{}
+		After    map[string]interface{}
+		Expected interface{}
+	}{
+		"field set": {
+			Before:   map[string]interface{}{},
+			After:    map[string]interface{}{"kms_key_name": "key-1"},
+			Expected: "key-1",
+		},
+		"field changed": {
+			Before:   map[string]interface{}{"kms_key": "key-1", "kms_key_name": "key-1"},
+			After:    map[string]interface{}{"kms_key": "key-1", "kms_key_name": "key-2"},
+			Expected: "key-2",
+		},
+		"field removed": {
+			Before:   map[string]interface{}{"kms_key": "key-1", "kms_key_name": "key-1"},
+			After:    map[string]interface{}{"kms_key": "key-1"},
+			Expected: "key-1",
+		},
+		"alias set": {
+			Before:   map[string]interface{}{},
+			After:    map[string]interface{}{"kms_key": "key-1"},
+			Expected: "key-1",
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			Before: tc.Before,
+			After:  tc.After,
+		}
+		if err := tpgresource.AliasFieldLogic("kms_key_name", "kms_key", d); err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if got := d.After["kms_key"]; got != tc.Expected {
+			t.Errorf("bad: %s, expected kms_key to be %v, got %v", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestAliasedKey(t *testing.T) {
+	aliasSchema := map[string]*schema.Schema{
+		"kms_key_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"kms_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+	}
+
+	cases := map[string]struct {
+		Config   map[string]cty.Value
+		Expected string
+	}{
+		"field set": {
+			Config: map[string]cty.Value{
+				"kms_key_name": cty.StringVal("key-1"),
+				"kms_key":      cty.NullVal(cty.String),
+			},
+			Expected: "kms_key_name",
+		},
+		"alias set": {
+			Config: map[string]cty.Value{
+				"kms_key_name": cty.NullVal(cty.String),
+				"kms_key":      cty.StringVal("key-1"),
+			},
+			Expected: "kms_key",
+		},
+		"neither set": {
+			Config: map[string]cty.Value{
+				"kms_key_name": cty.NullVal(cty.String),
+				"kms_key":      cty.NullVal(cty.String),
+			},
+			Expected: "kms_key_name",
+		},
+	}
+
+	for tn, tc := range cases {
+		d := (&schema.Resource{Schema: aliasSchema}).Data(&terraform.InstanceState{
+			ID:        "id",
+			RawConfig: cty.ObjectVal(tc.Config),
+		})
+		if got := tpgresource.AliasedKey(d, "kms_key_name", "kms_key"); got != tc.Expected {
+			t.Errorf("bad: %s, expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+
+	d := (&schema.Resource{Schema: aliasSchema}).Data(nil)
+	if got := tpgresource.AliasedKey(d, "kms_key_name", "kms_key"); got != "kms_key_name" {
+		t.Errorf("bad: without a configuration, expected %q, got %q", "kms_key_name", got)
+	}
+}
+
+func TestSetAliasedField(t *testing.T) {
+	cases := map[string]struct {
+		State    map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"field in state": {
+			State:    map[string]interface{}{"kms_key_name": "key-1", "kms_key": "key-1"},
+			Expected: map[string]interface{}{"kms_key_name": "key-2", "kms_key": "key-2"},
+		},
+		"alias in state": {
+			State:    map[string]interface{}{"kms_key_name": "", "kms_key": "key-1"},
+			Expected: map[string]interface{}{"kms_key_name": "", "kms_key": "key-2"},
+		},
+		"import": {
+			State:    map[string]interface{}{},
+			Expected: map[string]interface{}{"kms_key_name": "key-2", "kms_key": "key-2"},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDataMock{
+			FieldsInSchema: tc.State,
+		}
+		if err := tpgresource.SetAliasedField(d, "kms_key_name", "key-2", "kms_key"); err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if !reflect.DeepEqual(d.FieldsInSchema, tc.Expected) {
+			t.Errorf("bad: %s, expected %v, got %v", tn, tc.Expected, d.FieldsInSchema)
+		}
+	}
+}
+
+func TestGetProviderDefault(t *testing.T) {
+	config := &transport_tpg.Config{
+		Project: "my-project",
+		Region:  "us-central1",
+		Zone:    "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a",
+	}
+
+	cases := map[string]struct {
+		Attribute   string
+		Config      *transport_tpg.Config
+		Expected    string
+		ExpectError bool
+	}{
+		"project": {
+			Attribute: "project",
+			Config:    config,
+			Expected:  "my-project",
+		},
+		"region": {
+			Attribute: "region",
+			Config:    config,
+			Expected:  "us-central1",
+		},
+		"zone self link": {
+			Attribute: "zone",
+			Config:    config,
+			Expected:  "us-central1-a",
+		},
+		"unset": {
+			Attribute:   "zone",
+			Config:      &transport_tpg.Config{},
+			ExpectError: true,
+		},
+		"unknown attribute": {
+			Attribute:   "location",
+			Config:      config,
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		got, err := tpgresource.GetProviderDefault(tc.Attribute, tc.Config)
+		if tc.ExpectError {
+			if err == nil {
+				t.Errorf("bad: %s, expected an error", tn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+		if got != tc.Expected {
+			t.Errorf("bad: %s, expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestUniqueItemsLogic(t *testing.T) {
+	cases := map[string]struct {
+		ListPath    string
+		After       map[string]interface{}
+		ExpectError bool
+	}{
+		"unique items": {
+			ListPath: "ports",
+			After: map[string]interface{}{
+				"ports": []interface{}{"80", "443"},
+			},
+		},
+		"duplicate items": {
+			ListPath: "ports",
+			After: map[string]interface{}{
+				"ports": []interface{}{"80", "443", "80"},
+			},
+			ExpectError: true,
+		},
+		"unset list": {
+			ListPath: "ports",
+			After:    map[string]interface{}{},
+		},
+		"duplicate items in a nested list": {
+			ListPath: "rules.*.ports",
+			After: map[string]interface{}{
+				"rules":         []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+				"rules.0.ports": []interface{}{"80"},
+				"rules.1.ports": []interface{}{"443", "443"},
+			},
+			ExpectError: true,
+		},
+		"same items in different nested lists": {
+			ListPath: "rules.*.ports",
+			After: map[string]interface{}{
+				"rules":         []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+				"rules.0.ports": []interface{}{"80"},
+				"rules.1.ports": []interface{}{"80"},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &tpgresource.ResourceDiffMock{
+			After: tc.After,
+		}
+		err := tpgresource.UniqueItemsLogic(tc.ListPath, d)
+		if tc.ExpectError && err == nil {
+			t.Errorf("bad: %s, expected an error", tn)
+		}
+		if !tc.ExpectError && err != nil {
+			t.Errorf("bad: %s, unexpected error: %s", tn, err)
+		}
+	}
+}
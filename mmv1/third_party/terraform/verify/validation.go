@@ -154,6 +154,24 @@ func ValidateEnum(values []string) schema.SchemaValidateFunc {
 	return validation.StringInSlice(values, false)
 }
 
+// ValidateEnumWithDeprecations validates like ValidateEnum, and returns a
+// warning with the deprecation message when a deprecated value is used.
+func ValidateEnumWithDeprecations(values []string, deprecated map[string]string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		warnings, errors = ValidateEnum(values)(v, k)
+		if msg, ok := deprecated[v]; ok {
+			warnings = append(warnings, fmt.Sprintf("%q: value %q is deprecated. %s", k, v, msg))
+		}
+		return
+	}
+}
+
 func ValidateRFC1918Network(min, max int) schema.SchemaValidateFunc {
 	return func(i interface{}, k string) (s []string, es []error) {
 
@@ -176,6 +194,45 @@ func ValidateRFC1918Network(min, max int) schema.SchemaValidateFunc {
 	}
 }
 
+var quantityRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]*)?|\.[0-9]+)((?:[eE][+-]?[0-9]+)?)(Ki|Mi|Gi|Ti|Pi|Ei|k|M|G|T|P|E)?$`)
+
+var quantityMultipliers = map[string]float64{
+	"":   1,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// ParseQuantity returns the number of bytes in a Kubernetes-style quantity
+// such as "2Gi", "2048Mi" or "500M". A quantity without a suffix is in bytes.
+func ParseQuantity(quantity string) (float64, error) {
+	m := quantityRegex.FindStringSubmatch(quantity)
+	if m == nil {
+		return 0, fmt.Errorf("%q is not a valid quantity", quantity)
+	}
+	number, err := strconv.ParseFloat(m[1]+m[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid quantity: %s", quantity, err)
+	}
+	return number * quantityMultipliers[m[3]], nil
+}
+
+func ValidateQuantity(v interface{}, k string) (warnings []string, errors []error) {
+	if _, err := ParseQuantity(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
 func ValidateRFC3339Time(v interface{}, k string) (warnings []string, errors []error) {
 	time := v.(string)
 	if len(time) != 5 || time[2] != ':' {
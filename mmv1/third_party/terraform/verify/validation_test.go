@@ -159,6 +159,82 @@ func TestValidateRFC3339Time(t *testing.T) {
 	}
 }
 
+func TestValidateEnumWithDeprecations(t *testing.T) {
+	f := ValidateEnumWithDeprecations([]string{"", "NEW", "OLD"}, map[string]string{"OLD": "Use NEW instead."})
+
+	cases := map[string]struct {
+		Value          interface{}
+		ExpectWarnings int
+		ExpectErrors   int
+	}{
+		"allowed value":    {Value: "NEW"},
+		"empty value":      {Value: ""},
+		"deprecated value": {Value: "OLD", ExpectWarnings: 1},
+		"unknown value":    {Value: "OTHER", ExpectErrors: 1},
+		"non-string value": {Value: 1, ExpectErrors: 1},
+	}
+
+	for tn, tc := range cases {
+		ws, es := f(tc.Value, "mode")
+		if len(ws) != tc.ExpectWarnings {
+			t.Errorf("bad: %s, expected %d warnings, got %v", tn, tc.ExpectWarnings, ws)
+		}
+		if len(es) != tc.ExpectErrors {
+			t.Errorf("bad: %s, expected %d errors, got %v", tn, tc.ExpectErrors, es)
+		}
+	}
+}
+
+func TestValidateQuantity(t *testing.T) {
+	cases := []StringValidationTestCase{
+		// No errors
+		{TestName: "bytes", Value: "1024"},
+		{TestName: "binary suffix", Value: "2Gi"},
+		{TestName: "decimal suffix", Value: "500M"},
+		{TestName: "fraction", Value: "1.5Ti"},
+		{TestName: "exponent", Value: "1e3"},
+		{TestName: "exa suffix", Value: "1E"},
+
+		// With errors
+		{TestName: "empty", Value: "", ExpectError: true},
+		{TestName: "negative", Value: "-1Gi", ExpectError: true},
+		{TestName: "unknown suffix", Value: "2GB", ExpectError: true},
+		{TestName: "lowercase binary suffix", Value: "2gi", ExpectError: true},
+		{TestName: "suffix only", Value: "Gi", ExpectError: true},
+		{TestName: "space", Value: "2 Gi", ExpectError: true},
+	}
+
+	es := TestStringValidationCases(cases, ValidateQuantity)
+	if len(es) > 0 {
+		t.Errorf("Failed to validate quantities: %v", es)
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	cases := map[string]float64{
+		"0":      0,
+		"1024":   1024,
+		"1Ki":    1024,
+		"2048Mi": 2 << 30,
+		"2Gi":    2 << 30,
+		"0.5Gi":  1 << 29,
+		"500M":   500e6,
+		"1e3":    1000,
+		"1E":     1e18,
+	}
+
+	for quantity, expected := range cases {
+		got, err := ParseQuantity(quantity)
+		if err != nil {
+			t.Errorf("ParseQuantity(%q) returned error: %s", quantity, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseQuantity(%q) = %v, want %v", quantity, got, expected)
+		}
+	}
+}
+
 func TestValidateRFC1035Name(t *testing.T) {
 	cases := []struct {
 		TestName    string